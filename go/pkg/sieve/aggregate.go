@@ -0,0 +1,180 @@
+package sieve
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+// SumFirstNPrimes - returns the sum of the first n primes, e.g. 2+3+5+7 = 17 for n=4.
+// Returns an error wrapping ErrOverflow if the sum doesn't fit in an int64, or ErrNegativeIndex if n is negative
+func (s *PrimeNumberSieve) SumFirstNPrimes(n int64) (int64, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("%w: cannot sum the first %d primes", ErrNegativeIndex, n)
+	}
+
+	// the kth prime is always above k*ln(k), so the sum is at least the integral of x*ln(x) from 1 to n.
+	// If even that overflows there's no point sieving
+	fn := float64(n)
+	if n > 1 && fn*fn*math.Log(fn)/2-fn*fn/4+0.25 > math.MaxInt64 {
+		return 0, fmt.Errorf("%w: the sum of the first %d primes exceeds an int64", ErrOverflow, n)
+	}
+
+	var sum int64
+	for _, p := range s.firstPrimes(n) {
+		if sum > math.MaxInt64-p {
+			return 0, fmt.Errorf("%w: the sum of the first %d primes exceeds an int64", ErrOverflow, n)
+		}
+		sum += p
+	}
+	return sum, nil
+}
+
+// DigitCountDistribution - returns how many primes <= limit have each number of decimal digits,
+// e.g. limit=100 gives {1: 4, 2: 21}
+func (s *PrimeNumberSieve) DigitCountDistribution(limit int64) map[int]int64 {
+	res := make(map[int]int64)
+	primes := s.PrimesUpToShared(limit)
+
+	// primes are ascending, so step the digit count up each time the next power of 10 is reached.
+	// nextPower is unsigned as 10^19 is needed to bound the largest int64 values
+	digits, nextPower := 1, uint64(10)
+	for i := 0; i < primes.Len(); i++ {
+		p := uint64(primes.At(i))
+		for p >= nextPower {
+			digits++
+			nextPower *= 10
+		}
+		res[digits]++
+	}
+	return res
+}
+
+// ProductOfPrimesInRangeMod - returns the product of every prime in [lo, hi], reduced mod m, without the product
+// ever being materialized. An empty range gives 1 mod m, and m must be positive or 0 is returned
+func (s *PrimeNumberSieve) ProductOfPrimesInRangeMod(lo, hi, m int64) int64 {
+	if m <= 0 {
+		return 0
+	}
+
+	product := 1 % m
+	for _, p := range s.PrimesInRange(lo, hi) {
+		product = mulmod(product, p%m, m)
+	}
+	return product
+}
+
+// PiAtPowers - returns pi(x), the number of primes <= x, at each power x = base^k <= limit keyed by k, for plotting
+// prime growth on a log scale, e.g. base 10 and limit 1000 gives {1: 4, 2: 25, 3: 168}.
+// Non-integer powers are rounded down, and a base of 1 or less has no powers to report
+func (s *PrimeNumberSieve) PiAtPowers(base float64, limit int64) map[int]int64 {
+	res := make(map[int]int64)
+	if base <= 1 {
+		return res
+	}
+
+	primes := s.PrimesUpToShared(limit)
+	for k := 1; ; k++ {
+		power := math.Pow(base, float64(k))
+		if power > float64(limit) {
+			break
+		}
+		x := int64(power)
+		res[k] = int64(sort.Search(primes.Len(), func(i int) bool { return primes.At(i) > x }))
+	}
+	return res
+}
+
+// FirstNCoprimeModuli - returns the first n primes, which are pairwise coprime, along with their product (the
+// primorial p_n#). Together they form a ready made basis for the Chinese Remainder Theorem
+func (s *PrimeNumberSieve) FirstNCoprimeModuli(n int64) ([]int64, *big.Int) {
+	// copy as firstPrimes may share memory with the cache
+	primes := append(make([]int64, 0), s.firstPrimes(n)...)
+
+	product := big.NewInt(1)
+	for _, p := range primes {
+		product.Mul(product, big.NewInt(p))
+	}
+	return primes, product
+}
+
+// PrimeReciprocalSum - returns the sum of 1/p over every prime p <= limit, which diverges like ln ln limit
+func (s *PrimeNumberSieve) PrimeReciprocalSum(limit int64) float64 {
+	primes := s.PrimesUpToShared(limit)
+
+	// add the smallest terms first so they aren't lost to rounding against the running total
+	var sum float64
+	for i := primes.Len() - 1; i >= 0; i-- {
+		sum += 1 / float64(primes.At(i))
+	}
+	return sum
+}
+
+// ReducePrimes - folds fn over every prime p where lo <= p <= hi in ascending order, starting from init, so any
+// aggregate (a count, sum, product or histogram) is found in one pass without materializing the primes. Like
+// PrimesInRange only the window is sieved, a segment of sqrt(hi) at a time or a number at a time if it's narrow, and
+// any lo below 2 is treated as 2. A generic function rather than a method, as Go methods can't have type parameters
+func ReducePrimes[T any](s *PrimeNumberSieve, lo, hi int64, init T, fn func(acc T, prime int64) T) T {
+	if lo < 2 {
+		lo = 2
+	}
+	acc := init
+	if hi < lo {
+		return acc
+	}
+
+	if cached, ok := s.cachedWindow(lo, hi); ok {
+		for _, p := range cached {
+			acc = fn(acc, p)
+		}
+		return acc
+	}
+
+	if narrowWindow(lo, hi) {
+		forEachTestedPrime(lo, hi, func(p int64) {
+			acc = fn(acc, p)
+		})
+		return acc
+	}
+
+	// primes up to sqrt(hi) are enough to mark every composite in the window, and are folded first if they're in it
+	baseLimit := isqrt(hi)
+	primes := (&basicSieveOfEratosthenes{}).sieve(baseLimit)
+	for _, p := range primes {
+		if p >= lo {
+			acc = fn(acc, p)
+		}
+	}
+	if lo <= baseLimit {
+		lo = baseLimit + 1
+	}
+
+	forEachSegment(primes, lo, hi, baseLimit, func(low int64, segment []bool) bool {
+		for i, isPrime := range segment {
+			if isPrime {
+				acc = fn(acc, low+int64(i))
+			}
+		}
+		return true
+	})
+	return acc
+}
+
+// checksumModulus - the Mersenne prime 2^61 - 1 that PrimeChecksum reduces by
+const checksumModulus = 1<<61 - 1
+
+// PrimeChecksum - returns an order sensitive checksum of data, the sum of each element times the prime at its index,
+// data[0]*2 + data[1]*3 + data[2]*5 + ..., mod the prime 2^61 - 1. Elements are reduced into [0, 2^61 - 1) first so
+// negative values are handled, and every product is taken with mulmod so nothing overflows. Empty data sums to 0
+func (s *PrimeNumberSieve) PrimeChecksum(data []int64) int64 {
+	var sum int64
+	for i, p := range s.firstPrimes(int64(len(data))) {
+		v := data[i] % checksumModulus
+		if v < 0 {
+			v += checksumModulus
+		}
+		sum = (sum + mulmod(v, p%checksumModulus, checksumModulus)) % checksumModulus
+	}
+	return sum
+}
@@ -0,0 +1,176 @@
+package sieve
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSumFirstNPrimes(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	sum, err := sieve.SumFirstNPrimes(4)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(17), sum)
+
+	sum, err = sieve.SumFirstNPrimes(0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), sum)
+
+	sum, err = sieve.SumFirstNPrimes(1000)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3682913), sum)
+
+	_, err = sieve.SumFirstNPrimes(1 << 32)
+	assert.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = sieve.SumFirstNPrimes(-1)
+	assert.True(t, errors.Is(err, ErrNegativeIndex))
+}
+
+func TestDigitCountDistribution(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	dist := sieve.DigitCountDistribution(100)
+	assert.Equal(t, map[int]int64{1: 4, 2: 21}, dist)
+	assert.Equal(t, int64(25), dist[1]+dist[2])
+
+	assert.Equal(t, map[int]int64{1: 4, 2: 21, 3: 143, 4: 1061, 5: 8363, 6: 68906}, sieve.DigitCountDistribution(1000000))
+	assert.Equal(t, map[int]int64{}, sieve.DigitCountDistribution(1))
+}
+
+func TestProductOfPrimesInRangeMod(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, int64((2*3*5*7)%100), sieve.ProductOfPrimesInRangeMod(2, 7, 100))
+	assert.Equal(t, int64(10), sieve.ProductOfPrimesInRangeMod(-5, 10, 100))
+	assert.Equal(t, int64(11*13%7), sieve.ProductOfPrimesInRangeMod(11, 16, 7))
+
+	// empty ranges are the empty product
+	assert.Equal(t, int64(1), sieve.ProductOfPrimesInRangeMod(14, 16, 100))
+	assert.Equal(t, int64(0), sieve.ProductOfPrimesInRangeMod(14, 16, 1))
+
+	// invalid moduli
+	assert.Equal(t, int64(0), sieve.ProductOfPrimesInRangeMod(2, 7, 0))
+	assert.Equal(t, int64(0), sieve.ProductOfPrimesInRangeMod(2, 7, -3))
+
+	// compare against big.Int for a product far too large for int64
+	want := big.NewInt(1)
+	for _, p := range sieve.PrimesInRange(1000, 2000) {
+		want.Mul(want, big.NewInt(p))
+	}
+	m := int64(1000000007)
+	assert.Equal(t, want.Mod(want, big.NewInt(m)).Int64(), sieve.ProductOfPrimesInRangeMod(1000, 2000, m))
+}
+
+func TestPiAtPowers(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, map[int]int64{1: 4, 2: 25, 3: 168}, sieve.PiAtPowers(10, 1000))
+	assert.Equal(t, map[int]int64{1: 4, 2: 25, 3: 168}, sieve.PiAtPowers(10, 9999))
+	assert.Equal(t, map[int]int64{1: 1, 2: 2, 3: 4, 4: 6, 5: 11, 6: 18}, sieve.PiAtPowers(2, 100))
+	assert.Equal(t, map[int]int64{1: 4, 2: 25, 3: 168, 4: 1229, 5: 9592, 6: 78498}, sieve.PiAtPowers(10, 1000000))
+	assert.Equal(t, map[int]int64{}, sieve.PiAtPowers(1, 1000))
+	assert.Equal(t, map[int]int64{}, sieve.PiAtPowers(10, 5))
+}
+
+func TestFirstNCoprimeModuli(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	moduli, product := sieve.FirstNCoprimeModuli(3)
+	assert.Equal(t, []int64{2, 3, 5}, moduli)
+	assert.Equal(t, big.NewInt(30), product)
+
+	moduli, product = sieve.FirstNCoprimeModuli(0)
+	assert.Equal(t, []int64{}, moduli)
+	assert.Equal(t, big.NewInt(1), product)
+
+	// the product of the first 20 primes no longer fits in an int64
+	moduli, product = sieve.FirstNCoprimeModuli(20)
+	assert.Len(t, moduli, 20)
+	want, _ := new(big.Int).SetString("557940830126698960967415390", 10)
+	assert.Equal(t, want, product)
+
+	// modifying the moduli leaves the sieve's primes alone
+	moduli[0] = 4
+	assert.Equal(t, int64(2), sieve.NthPrime(0))
+}
+
+func TestPrimeReciprocalSum(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.InDelta(t, 1.8028172, sieve.PrimeReciprocalSum(100), 1e-7)
+	assert.Equal(t, 0.5, sieve.PrimeReciprocalSum(2))
+	assert.Equal(t, float64(0), sieve.PrimeReciprocalSum(1))
+
+	// the sum grows with the limit, though ever more slowly
+	prev := float64(0)
+	for limit := int64(2); limit <= 1000000; limit *= 3 {
+		sum := sieve.PrimeReciprocalSum(limit)
+		assert.Greater(t, sum, prev, "limit=%d", limit)
+		prev = sum
+	}
+}
+
+func TestReducePrimes(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	count := func(acc int64, _ int64) int64 { return acc + 1 }
+	sum := func(acc int64, p int64) int64 { return acc + p }
+
+	for _, r := range [][2]int64{{0, 1}, {0, 2}, {2, 100}, {10, 30}, {1000, 999}, {-50, 7919}, {999000, 1000000}} {
+		primes := sieve.PrimesInRange(r[0], r[1])
+		var want int64
+		for _, p := range primes {
+			want += p
+		}
+		assert.Equal(t, int64(len(primes)), ReducePrimes(sieve, r[0], r[1], 0, count), "range %v", r)
+		assert.Equal(t, want, ReducePrimes(sieve, r[0], r[1], 0, sum), "range %v", r)
+	}
+	assert.Equal(t, sieve.PrimePi(1000000), ReducePrimes(sieve, 0, 1000000, 0, count))
+
+	// narrow windows are folded a number at a time, right up to the top of an int64
+	lo := int64(1000000000000)
+	assert.Equal(t, int64(len(sieve.PrimesInRange(lo, lo+10000))), ReducePrimes(sieve, lo, lo+10000, 0, count))
+	assert.Equal(t, int64(9223372036854775783), ReducePrimes(sieve, math.MaxInt64-100, math.MaxInt64, 0, sum))
+
+	// served from the cache once it covers the range, with the same answers
+	sumFirst, _ := sieve.SumFirstNPrimes(1000)
+	sieve.Prewarm(2000)
+	assert.Equal(t, sumFirst, ReducePrimes(sieve, 2, sieve.NthPrime(999), 0, sum))
+	assert.Equal(t, int64(1000), ReducePrimes(sieve, 2, sieve.NthPrime(999), 0, count))
+
+	// any accumulator type works, here a histogram of last digits
+	digits := ReducePrimes(sieve, 10, 10000, map[int64]int{}, func(acc map[int64]int, p int64) map[int64]int {
+		acc[p%10]++
+		return acc
+	})
+	assert.Len(t, digits, 4)
+	assert.Equal(t, 1229-4, digits[1]+digits[3]+digits[7]+digits[9])
+}
+
+func TestPrimeChecksum(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// 1*2 + 2*3 + 3*5
+	assert.Equal(t, int64(23), sieve.PrimeChecksum([]int64{1, 2, 3}))
+	assert.Equal(t, int64(0), sieve.PrimeChecksum(nil))
+
+	// deterministic for identical input, across sieves too, while any reordering changes it
+	data := []int64{42, -7, 1 << 62, math.MaxInt64, math.MinInt64, 0, 99991}
+	sum := sieve.PrimeChecksum(data)
+	assert.Equal(t, sum, sieve.PrimeChecksum(append([]int64{}, data...)))
+	assert.Equal(t, sum, NewPrimeNumberSieve().PrimeChecksum(data))
+	assert.NotEqual(t, sieve.PrimeChecksum([]int64{1, 2, 3}), sieve.PrimeChecksum([]int64{2, 1, 3}))
+	assert.NotEqual(t, sum, sieve.PrimeChecksum([]int64{-7, 42, 1 << 62, math.MaxInt64, math.MinInt64, 0, 99991}))
+
+	// and exact however large the elements, checked against big.Int arithmetic
+	want := new(big.Int)
+	m := big.NewInt(checksumModulus)
+	for i, v := range data {
+		want.Add(want, new(big.Int).Mul(big.NewInt(v), big.NewInt(sieve.NthPrime(int64(i)))))
+	}
+	assert.Equal(t, want.Mod(want, m).Int64(), sum)
+}
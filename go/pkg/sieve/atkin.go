@@ -0,0 +1,60 @@
+package sieve
+
+// sieveOfAtkin - uses the sieve of Atkin to return a list of primes from 2 - n.
+// Rather than crossing off multiples of each prime, it toggles candidates by the number of solutions to three
+// quadratic forms, then removes the multiples of squares of primes which the forms can't tell apart from primes:
+// https://en.wikipedia.org/wiki/Sieve_of_Atkin
+type sieveOfAtkin struct{}
+
+// name - implementation of the sieve interface
+func (a *sieveOfAtkin) name() string {
+	return "atkin"
+}
+
+// sieve - implementation of the sieve of Atkin
+func (a *sieveOfAtkin) sieve(n int64) []int64 {
+	if n < 2 {
+		return make([]int64, 0)
+	}
+
+	// isPrime[k] is toggled once for every solution of the form matching k's residue mod 12, leaving candidates with
+	// an odd number of solutions marked. Every prime above 3 falls into exactly one of the forms
+	isPrime := make([]bool, n+1)
+	for x := int64(1); x*x <= n; x++ {
+		for y := int64(1); y*y <= n; y++ {
+			if k := 4*x*x + y*y; k <= n && (k%12 == 1 || k%12 == 5) {
+				isPrime[k] = !isPrime[k]
+			}
+			if k := 3*x*x + y*y; k <= n && k%12 == 7 {
+				isPrime[k] = !isPrime[k]
+			}
+			if k := 3*x*x - y*y; x > y && k <= n && k%12 == 11 {
+				isPrime[k] = !isPrime[k]
+			}
+		}
+	}
+
+	// squarefree is the only other property the forms need, so eliminate every multiple of a prime's square
+	for r := int64(5); r*r <= n; r++ {
+		if isPrime[r] {
+			for i := r * r; i <= n; i += r * r {
+				isPrime[i] = false
+			}
+		}
+	}
+
+	// 2 and 3 divide 12 so none of the forms pick them up, add them directly
+	res := make([]int64, 0)
+	for _, p := range []int64{2, 3} {
+		if p <= n {
+			res = append(res, p)
+		}
+	}
+	for i := int64(5); i <= n; i++ {
+		if isPrime[i] {
+			res = append(res, i)
+		}
+	}
+
+	return res
+}
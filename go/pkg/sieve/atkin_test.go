@@ -0,0 +1,38 @@
+package sieve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSieveOfAtkinMatchesBasic(t *testing.T) {
+	for _, n := range []int64{-1, 0, 1, 2, 3, 4, 5, 6, 7, 11, 12, 13, 25, 49, 100, 541, 7919, 10000, 65537, 99991, 100000} {
+		assert.Equal(t, (&basicSieveOfEratosthenes{}).sieve(n), (&sieveOfAtkin{}).sieve(n), "n=%d", n)
+	}
+
+	// every bound up to a few thousand, so no boundary or residue class is missed
+	for n := int64(0); n <= 3000; n++ {
+		if !assert.Equal(t, (&basicSieveOfEratosthenes{}).sieve(n), (&sieveOfAtkin{}).sieve(n), "n=%d", n) {
+			break
+		}
+	}
+}
+
+func BenchmarkSieveOfAtkin(b *testing.B) {
+	n := int64(10000000)
+
+	b.Run("eratosthenes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			(&basicSieveOfEratosthenes{}).sieve(n)
+		}
+	})
+
+	b.Run("atkin", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			(&sieveOfAtkin{}).sieve(n)
+		}
+	})
+}
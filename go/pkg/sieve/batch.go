@@ -0,0 +1,60 @@
+package sieve
+
+import (
+	"runtime"
+	"sync"
+)
+
+// NthPrimeMany - returns the nth prime for each of indices, in the same order, with 0 for any negative index.
+// When the largest index fits within the memory limit it is sieved once up front so every lookup is served from the
+// cache. Otherwise each index is streamed separately, running at most WithBatchConcurrency lookups at a time
+func (s *PrimeNumberSieve) NthPrimeMany(indices []int64) []int64 {
+	res := make([]int64, len(indices))
+
+	// indices beyond maxPrimeIndex are answered with 0 without sieving, so only valid ones size the prewarm
+	var largest int64 = -1
+	for _, n := range indices {
+		if n > largest && n <= maxPrimeIndex {
+			largest = n
+		}
+	}
+	if !s.exceedsMemoryLimit(EstimateUpperBound(largest)) {
+		s.Prewarm(largest + 1)
+	}
+
+	// with a single lookup at a time (e.g. GOMAXPROCS=1) goroutines only add overhead, so run them inline
+	limit := s.batchLimit()
+	tracef(s.trace, "looking up %d indices with up to %d at once", len(indices), limit)
+	if limit == 1 {
+		for i, n := range indices {
+			res[i] = s.NthPrime(n)
+		}
+		return res
+	}
+
+	// a buffered channel acts as the semaphore bounding how many lookups run at once
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i, n := range indices {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, n int64) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			res[i] = s.NthPrime(n)
+		}(i, n)
+	}
+	wg.Wait()
+
+	return res
+}
+
+// batchLimit - the number of lookups NthPrimeMany may run at once, one per available CPU unless configured
+func (s *PrimeNumberSieve) batchLimit() int {
+	if s.batchConcurrency > 0 {
+		return s.batchConcurrency
+	}
+	return runtime.GOMAXPROCS(0)
+}
@@ -0,0 +1,113 @@
+package sieve
+
+import (
+	"bytes"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// concurrencyTracer - tracks the most NthPrime spans that were ever open at the same time
+type concurrencyTracer struct {
+	active, max int64
+}
+
+func (c *concurrencyTracer) Start(string) Span {
+	active := atomic.AddInt64(&c.active, 1)
+	for {
+		max := atomic.LoadInt64(&c.max)
+		if active <= max || atomic.CompareAndSwapInt64(&c.max, max, active) {
+			break
+		}
+	}
+	return concurrencySpan{c}
+}
+
+// concurrencySpan - closes its span on the concurrencyTracer when ended
+type concurrencySpan struct {
+	tracer *concurrencyTracer
+}
+
+func (concurrencySpan) SetAttribute(string, interface{}) {}
+func (c concurrencySpan) End()                           { atomic.AddInt64(&c.tracer.active, -1) }
+
+func TestNthPrimeMany(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, []int64{71, 2, 0, 541, 7793, 2}, sieve.NthPrimeMany([]int64{19, 0, -1, 99, 986, 0}))
+	assert.Equal(t, []int64{}, sieve.NthPrimeMany([]int64{}))
+}
+
+func TestNthPrimeManyOutOfRangeIndices(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// indices past the last prime in an int64 don't size the prewarm, which would otherwise sieve to math.MaxInt64
+	start := time.Now()
+	assert.Equal(t, []int64{13, 0, 0}, sieve.NthPrimeMany([]int64{5, maxPrimeIndex + 1, -1}))
+	assert.Equal(t, []int64{0, 13}, sieve.NthPrimeMany([]int64{math.MaxInt64, 5}))
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, EstimateUpperBound(5), sieve.cache.bound)
+
+	// nor does Prewarm sieve for them
+	sieve = NewPrimeNumberSieve()
+	sieve.Prewarm(maxPrimeIndex + 2)
+	sieve.Prewarm(math.MaxInt64)
+	assert.Zero(t, sieve.cache.bound)
+}
+
+func TestNthPrimeManyBatchConcurrency(t *testing.T) {
+	tracer := &concurrencyTracer{}
+
+	// a tiny memory limit streams every lookup separately, rather than answering them all from one cached sieve
+	sieve := NewPrimeNumberSieve(WithMemoryLimit(1), WithBatchConcurrency(2), WithTracer(tracer))
+
+	indices := make([]int64, 500)
+	for i := range indices {
+		indices[i] = int64(i * 7)
+	}
+	res := sieve.NthPrimeMany(indices)
+
+	expected := NewPrimeNumberSieve().PrimesUpTo(40000)
+	for i, n := range indices {
+		assert.Equal(t, expected[n], res[i], "n=%d", n)
+	}
+	assert.LessOrEqual(t, atomic.LoadInt64(&tracer.max), int64(2))
+	assert.Greater(t, atomic.LoadInt64(&tracer.max), int64(0))
+}
+
+func TestNthPrimeManyConcurrentCallers(t *testing.T) {
+	sieve := NewPrimeNumberSieve(WithBatchConcurrency(4))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.Equal(t, []int64{541, 3581, 17393}, sieve.NthPrimeMany([]int64{99, 500, 2000}))
+		}()
+	}
+	wg.Wait()
+}
+
+func TestNthPrimeManySingleProcessor(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+
+	// the lookup limit is traced, and with a single processor the lookups run inline one at a time
+	var trace bytes.Buffer
+	tracer := &concurrencyTracer{}
+	sieve := NewPrimeNumberSieve(WithTrace(&trace), WithTracer(tracer), WithMemoryLimit(1))
+	assert.Equal(t, []int64{71, 2, 0, 541, 7793}, sieve.NthPrimeMany([]int64{19, 0, -1, 99, 986}))
+	assert.Contains(t, trace.String(), "looking up 5 indices with up to 1 at once")
+	assert.Equal(t, int64(1), atomic.LoadInt64(&tracer.max))
+
+	// while more processors allow as many lookups at once
+	runtime.GOMAXPROCS(2)
+	trace.Reset()
+	sieve.NthPrimeMany([]int64{19, 0})
+	assert.Contains(t, trace.String(), "looking up 2 indices with up to 2 at once")
+}
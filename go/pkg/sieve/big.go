@@ -0,0 +1,111 @@
+package sieve
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// bigSegmentSize - how many numbers NthPrimeBig sieves at a time beyond the int64 range
+const bigSegmentSize = 1 << 20
+
+// NthPrimeBig - same as NthPrime, but for indices of any size, returning primes beyond the int64 range as a big.Int.
+// Indices whose prime fits in an int64 are answered by PrimeAt. Past that the primes are streamed a segment at a time
+// from 2^63, below which there are known to be exactly maxPrimeIndex + 1, using big.Int bounds and segment offsets so
+// nothing can overflow. The primes up to the square root of the bound are still needed to sieve with, so an error
+// wrapping ErrMemoryLimitExceeded is returned if they would exceed the budget set by WithMemoryLimit, or one wrapping
+// ErrIndexTooLarge if they don't fit in an int64. Returns an error wrapping ErrNegativeIndex for a nil or negative index
+func (s *PrimeNumberSieve) NthPrimeBig(n *big.Int) (*big.Int, error) {
+	if n == nil || n.Sign() < 0 {
+		return nil, fmt.Errorf("%w: %v", ErrNegativeIndex, n)
+	}
+	if n.IsInt64() && n.Int64() <= maxPrimeIndex {
+		prime, err := s.PrimeAt(n.Int64())
+		if err != nil {
+			return nil, err
+		}
+		return big.NewInt(prime), nil
+	}
+
+	upperBounds, ok := bigUpperBound(n)
+	if !ok {
+		return nil, fmt.Errorf("%w: no bound for index %v fits in a float64", ErrIndexTooLarge, n)
+	}
+
+	// the index of the wanted prime among those from 2^63 up
+	remaining := new(big.Int).Sub(n, big.NewInt(maxPrimeIndex+1))
+	low := new(big.Int).Lsh(big.NewInt(1), 63)
+	size := new(big.Int)
+	for {
+		baseLimit := new(big.Int).Sqrt(upperBounds)
+		if !baseLimit.IsInt64() {
+			return nil, fmt.Errorf("%w: sieving up to %v needs base primes beyond an int64", ErrIndexTooLarge, upperBounds)
+		}
+		if bytes := estimatedCacheBytes(baseLimit.Int64()); s.memoryLimit > 0 && bytes > s.memoryLimit {
+			return nil, fmt.Errorf("%w: sieving up to %v needs about %d bytes of base primes, the limit is %d",
+				ErrMemoryLimitExceeded, upperBounds, bytes, s.memoryLimit)
+		}
+		tracef(s.trace, "streaming n=%v from %v up to %v", n, low, upperBounds)
+		primes := (&segmentedSieve{}).sieve(baseLimit.Int64())
+
+		for low.Cmp(upperBounds) <= 0 {
+			size.Sub(upperBounds, low)
+			segmentSize := int64(bigSegmentSize)
+			if size.IsInt64() && size.Int64() < segmentSize {
+				segmentSize = size.Int64() + 1
+			}
+
+			for i, isPrime := range markBigSegment(primes, low, segmentSize) {
+				if !isPrime {
+					continue
+				}
+				if remaining.Sign() == 0 {
+					return new(big.Int).Add(low, big.NewInt(int64(i))), nil
+				}
+				remaining.Sub(remaining, big.NewInt(1))
+			}
+			low.Add(low, big.NewInt(segmentSize))
+		}
+
+		// a big.Int bound can always double, there's nothing to saturate at
+		upperBounds.Lsh(upperBounds, 1)
+	}
+}
+
+// bigUpperBound - EstimateUpperBound for indices of any size, Rosser's bound k(ln k + ln ln k) for k = n + 1.
+// ok is false when the index is too large to take the logarithm of as a float64
+func bigUpperBound(n *big.Int) (bound *big.Int, ok bool) {
+	if n.Cmp(big.NewInt(5)) < 0 {
+		return big.NewInt(20), true
+	}
+
+	k := new(big.Float).SetInt(new(big.Int).Add(n, big.NewInt(1)))
+	kf, _ := k.Float64()
+	if math.IsInf(kf, 0) {
+		return nil, false
+	}
+
+	bound, _ = k.Mul(k, big.NewFloat(math.Log(kf)+math.Log(math.Log(kf)))).Int(nil)
+	// Int truncates, so round up as EstimateUpperBound does
+	return bound.Add(bound, big.NewInt(1)), true
+}
+
+// markBigSegment - markSegment for a segment of size numbers from low, which may be beyond the int64 range. Only the
+// offset of each prime's first multiple needs big.Int arithmetic, the segment itself is indexed from 0.
+// low must be above every prime in primes so they don't mark themselves off
+func markBigSegment(primes []int64, low *big.Int, size int64) []bool {
+	segment := make([]bool, size)
+	for i := range segment {
+		segment[i] = true
+	}
+
+	p, rem := new(big.Int), new(big.Int)
+	for _, prime := range primes {
+		// the first multiple of prime at or above low is (prime - low mod prime) mod prime past it
+		rem.Mod(low, p.SetInt64(prime))
+		for i := (prime - rem.Int64()) % prime; i < size; i += prime {
+			segment[i] = false
+		}
+	}
+	return segment
+}
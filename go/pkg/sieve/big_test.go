@@ -0,0 +1,96 @@
+package sieve
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNthPrimeBig(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// agrees with NthPrime wherever the prime fits in an int64
+	for _, n := range []int64{0, 1, 19, 99, 2000, 1000000} {
+		prime, err := sieve.NthPrimeBig(big.NewInt(n))
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(sieve.NthPrime(n)), prime, "n=%d", n)
+	}
+
+	prime, err := sieve.NthPrimeBig(big.NewInt(99))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(541), prime.Int64())
+}
+
+func TestNthPrimeBigInvalid(t *testing.T) {
+	sieve := NewPrimeNumberSieve(WithMemoryLimit(1 << 30))
+
+	_, err := sieve.NthPrimeBig(big.NewInt(-5))
+	assert.True(t, errors.Is(err, ErrNegativeIndex))
+	_, err = sieve.NthPrimeBig(nil)
+	assert.True(t, errors.Is(err, ErrNegativeIndex))
+
+	// the first index beyond the int64 range needs the ~146 million primes up to 3*10^9 to sieve with
+	beyond := big.NewInt(maxPrimeIndex + 1)
+	prime, err := sieve.NthPrimeBig(beyond)
+	assert.Nil(t, prime)
+	assert.True(t, errors.Is(err, ErrMemoryLimitExceeded))
+
+	// while far larger indices fail gracefully rather than overflowing into a small or negative bound
+	huge := new(big.Int).Lsh(big.NewInt(1), 200)
+	_, err = sieve.NthPrimeBig(huge)
+	assert.True(t, errors.Is(err, ErrIndexTooLarge))
+
+	_, err = sieve.NthPrimeBig(new(big.Int).Lsh(big.NewInt(1), 2000))
+	assert.True(t, errors.Is(err, ErrIndexTooLarge))
+}
+
+func TestBigUpperBound(t *testing.T) {
+	// matches EstimateUpperBound to within float rounding
+	for _, n := range []int64{0, 4, 5, 99, 1000000, 1 << 40} {
+		bound, ok := bigUpperBound(big.NewInt(n))
+		assert.True(t, ok)
+		assert.InDelta(t, EstimateUpperBound(n), bound.Int64(), 1, "n=%d", n)
+	}
+
+	// and carries on past where EstimateUpperBound saturates
+	bound, ok := bigUpperBound(big.NewInt(maxPrimeIndex))
+	assert.True(t, ok)
+	assert.Equal(t, 1, bound.Cmp(big.NewInt(math.MaxInt64)))
+
+	_, ok = bigUpperBound(new(big.Int).Lsh(big.NewInt(1), 2000))
+	assert.False(t, ok)
+}
+
+func TestMarkBigSegment(t *testing.T) {
+	// within the int64 range it finds exactly the primes in the segment
+	primes := eratosthenes(isqrt(2000))
+	segment := markBigSegment(primes, big.NewInt(1000), 1001)
+	got := make([]int64, 0)
+	for i, isPrime := range segment {
+		if isPrime {
+			got = append(got, 1000+int64(i))
+		}
+	}
+	assert.Equal(t, NewPrimeNumberSieve().PrimesInRange(1000, 2000), got)
+
+	// beyond it, every number left unmarked has no factor among the primes and every marked one does
+	primes = eratosthenes(1000)
+	low := new(big.Int).Lsh(big.NewInt(1), 64)
+	v, rem := new(big.Int), new(big.Int)
+	for i, isPrime := range markBigSegment(primes, low, 5000) {
+		v.Add(low, big.NewInt(int64(i)))
+		divisible := false
+		for _, p := range primes {
+			if rem.Mod(v, big.NewInt(p)).Sign() == 0 {
+				divisible = true
+				break
+			}
+		}
+		if !assert.Equal(t, !divisible, isPrime, "v=%v", v) {
+			break
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package sieve
+
+import "math"
+
+// BloomFilter - an approximate set of primes, answering membership in a fixed amount of memory with no false negatives
+// but occasional false positives. Built by PrimeBloomFilter and safe for concurrent reads
+type BloomFilter struct {
+	bits   bitset
+	size   int64 // how many bits the filter has
+	hashes int   // how many bits each value sets
+}
+
+// defaultFalsePositiveRate - the rate PrimeBloomFilter sizes for when given one outside (0, 1)
+const defaultFalsePositiveRate = 0.01
+
+// PrimeBloomFilter - builds a Bloom filter holding the first n primes, sized so that a value which isn't one of them
+// tests positive with probability falsePositiveRate. A rate outside (0, 1) uses 1%, and n <= 0 gives an empty filter
+func (s *PrimeNumberSieve) PrimeBloomFilter(n int64, falsePositiveRate float64) *BloomFilter {
+	if !(falsePositiveRate > 0 && falsePositiveRate < 1) {
+		falsePositiveRate = defaultFalsePositiveRate
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	// the optimal size is -n ln(p) / ln(2)^2 bits, set by (size / n) ln(2) hashes
+	size := int64(math.Ceil(-float64(n) * math.Log(falsePositiveRate) / (math.Ln2 * math.Ln2)))
+	if size < 64 {
+		size = 64
+	}
+	hashes := 1
+	if n > 0 {
+		hashes = int(math.Round(float64(size) / float64(n) * math.Ln2))
+	}
+	if hashes < 1 {
+		hashes = 1
+	}
+
+	f := &BloomFilter{bits: newBitset(size), size: size, hashes: hashes}
+	for _, p := range s.firstPrimes(n) {
+		h1, h2 := bloomHashes(p)
+		for i := 0; i < f.hashes; i++ {
+			f.bits.set(f.index(h1, h2, i))
+		}
+	}
+	return f
+}
+
+// Contains - reports whether x may be one of the primes in the filter. False means x definitely isn't, true that it
+// is, or is a false positive
+func (f *BloomFilter) Contains(x int64) bool {
+	h1, h2 := bloomHashes(x)
+	for i := 0; i < f.hashes; i++ {
+		if !f.bits.get(f.index(h1, h2, i)) {
+			return false
+		}
+	}
+	return true
+}
+
+// index - the bit for the ith hash, by double hashing h1 + i*h2 so only two hashes are ever computed per value
+func (f *BloomFilter) index(h1, h2 uint64, i int) int64 {
+	return int64((h1 + uint64(i)*h2) % uint64(f.size))
+}
+
+// bloomHashes - two independent hashes of x from the splitmix64 finalizer, the second forced odd so the double hashed
+// indices don't collapse onto a few bits
+func bloomHashes(x int64) (h1, h2 uint64) {
+	h1 = mix64(uint64(x))
+	h2 = mix64(h1) | 1
+	return h1, h2
+}
+
+// mix64 - the splitmix64 finalizer, spreading every bit of z across the result
+func mix64(z uint64) uint64 {
+	z += 0x9e3779b97f4a7c15
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
+}
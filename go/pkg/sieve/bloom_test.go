@@ -0,0 +1,60 @@
+package sieve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrimeBloomFilter(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	const n = 100000
+	filter := sieve.PrimeBloomFilter(n, 0.01)
+
+	// no false negatives
+	primes := sieve.firstPrimes(n)
+	for _, p := range primes {
+		if !filter.Contains(p) {
+			assert.Fail(t, "inserted prime missing from the filter", "p=%d", p)
+			break
+		}
+	}
+
+	// and roughly the requested rate of false positives over the composites in the range
+	var composites, positives int
+	for x, i := int64(4), 0; x <= primes[n-1]; x++ {
+		for primes[i] < x {
+			i++
+		}
+		if primes[i] != x {
+			composites++
+			if filter.Contains(x) {
+				positives++
+			}
+		}
+	}
+	assert.InDelta(t, 0.01, float64(positives)/float64(composites), 0.005)
+}
+
+func TestPrimeBloomFilterEdgeCases(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// an empty filter contains nothing
+	empty := sieve.PrimeBloomFilter(0, 0.01)
+	for x := int64(-10); x <= 100; x++ {
+		assert.False(t, empty.Contains(x), "x=%d", x)
+	}
+	assert.False(t, sieve.PrimeBloomFilter(-1, 0.01).Contains(2))
+
+	// invalid rates fall back to the default
+	for _, rate := range []float64{0, -1, 1, 2} {
+		filter := sieve.PrimeBloomFilter(1000, rate)
+		assert.Equal(t, sieve.PrimeBloomFilter(1000, defaultFalsePositiveRate), filter, "rate=%v", rate)
+		assert.True(t, filter.Contains(7919))
+	}
+
+	// a lower rate takes more bits and hashes
+	loose, strict := sieve.PrimeBloomFilter(1000, 0.1), sieve.PrimeBloomFilter(1000, 0.0001)
+	assert.Less(t, loose.size, strict.size)
+	assert.Less(t, loose.hashes, strict.hashes)
+}
@@ -0,0 +1,164 @@
+package sieve
+
+import (
+	"fmt"
+	"io"
+)
+
+// SieveBuilder - an alternative to passing options to NewPrimeNumberSieve, configuring a sieve through chainable
+// methods and validating the combined configuration before anything is constructed.
+// A builder can be reused, each call to Build returns a new independent sieve
+type SieveBuilder struct {
+	trace        io.Writer
+	memoryLimit  int64
+	verifyOnLoad bool
+	adaptive     bool
+	tracer       Tracer
+	batch        int
+	primality    int
+	fanOutBuffer int
+	fanOutPolicy FanOutPolicy
+	workers      int
+	algorithm    Algorithm
+	crossCheck   bool
+	debugVerify  bool
+	sharedCache  *SharedCache
+}
+
+// NewSieveBuilder - Creates a new SieveBuilder with the default configuration
+func NewSieveBuilder() *SieveBuilder {
+	return &SieveBuilder{memoryLimit: defaultMemoryLimit}
+}
+
+// WithTrace - see the WithTrace option
+func (b *SieveBuilder) WithTrace(w io.Writer) *SieveBuilder {
+	b.trace = w
+	return b
+}
+
+// WithMemoryLimit - see the WithMemoryLimit option
+func (b *SieveBuilder) WithMemoryLimit(bytes int64) *SieveBuilder {
+	b.memoryLimit = bytes
+	return b
+}
+
+// WithVerifyOnLoad - see the WithVerifyOnLoad option
+func (b *SieveBuilder) WithVerifyOnLoad(verify bool) *SieveBuilder {
+	b.verifyOnLoad = verify
+	return b
+}
+
+// WithAdaptiveSegments - see the WithAdaptiveSegments option
+func (b *SieveBuilder) WithAdaptiveSegments(adaptive bool) *SieveBuilder {
+	b.adaptive = adaptive
+	return b
+}
+
+// WithTracer - see the WithTracer option
+func (b *SieveBuilder) WithTracer(t Tracer) *SieveBuilder {
+	b.tracer = t
+	return b
+}
+
+// WithBatchConcurrency - see the WithBatchConcurrency option
+func (b *SieveBuilder) WithBatchConcurrency(k int) *SieveBuilder {
+	b.batch = k
+	return b
+}
+
+// WithPrimalityCache - see the WithPrimalityCache option
+func (b *SieveBuilder) WithPrimalityCache(size int) *SieveBuilder {
+	b.primality = size
+	return b
+}
+
+// WithFanOut - see the WithFanOut option
+func (b *SieveBuilder) WithFanOut(buffer int, policy FanOutPolicy) *SieveBuilder {
+	b.fanOutBuffer, b.fanOutPolicy = buffer, policy
+	return b
+}
+
+// WithWorkers - see the WithWorkers option
+func (b *SieveBuilder) WithWorkers(n int) *SieveBuilder {
+	b.workers = n
+	return b
+}
+
+// WithAlgorithm - see the WithAlgorithm option
+func (b *SieveBuilder) WithAlgorithm(a Algorithm) *SieveBuilder {
+	b.algorithm = a
+	return b
+}
+
+// WithCrossCheck - see the WithCrossCheck option
+func (b *SieveBuilder) WithCrossCheck(enabled bool) *SieveBuilder {
+	b.crossCheck = enabled
+	return b
+}
+
+// WithDebugVerify - see the WithDebugVerify option
+func (b *SieveBuilder) WithDebugVerify(enabled bool) *SieveBuilder {
+	b.debugVerify = enabled
+	return b
+}
+
+// WithSharedCache - see the WithSharedCache option, every sieve built shares c
+func (b *SieveBuilder) WithSharedCache(c *SharedCache) *SieveBuilder {
+	b.sharedCache = c
+	return b
+}
+
+// Build - validates the configuration and creates a sieve from it, returning an error wrapping ErrInvalidConfig
+// if the configuration is invalid
+func (b *SieveBuilder) Build() (Sieve, error) {
+	if err := b.validate(); err != nil {
+		return nil, err
+	}
+
+	return NewPrimeNumberSieve(
+		WithTrace(b.trace),
+		WithMemoryLimit(b.memoryLimit),
+		WithVerifyOnLoad(b.verifyOnLoad),
+		WithAdaptiveSegments(b.adaptive),
+		WithTracer(b.tracer),
+		WithBatchConcurrency(b.batch),
+		WithPrimalityCache(b.primality),
+		WithFanOut(b.fanOutBuffer, b.fanOutPolicy),
+		WithWorkers(b.workers),
+		WithAlgorithm(b.algorithm),
+		WithCrossCheck(b.crossCheck),
+		WithDebugVerify(b.debugVerify),
+		WithSharedCache(b.sharedCache),
+	), nil
+}
+
+// validate - checks each setting, and every combination of settings, is valid
+func (b *SieveBuilder) validate() error {
+	if b.memoryLimit < 0 {
+		return fmt.Errorf("%w: memory limit must not be negative, got %d", ErrInvalidConfig, b.memoryLimit)
+	}
+	if b.batch < 0 {
+		return fmt.Errorf("%w: batch concurrency must not be negative, got %d", ErrInvalidConfig, b.batch)
+	}
+	if b.primality < 0 {
+		return fmt.Errorf("%w: primality cache size must not be negative, got %d", ErrInvalidConfig, b.primality)
+	}
+	if b.workers < 0 {
+		return fmt.Errorf("%w: workers must not be negative, got %d", ErrInvalidConfig, b.workers)
+	}
+	if _, ok := sieveFactories[b.algorithm]; !ok {
+		return fmt.Errorf("%w: unknown algorithm %d", ErrInvalidConfig, b.algorithm)
+	}
+
+	// only the segmented sieve has segments to adapt or share between workers
+	if b.algorithm != AlgorithmSegmented && b.workers > 1 {
+		return fmt.Errorf("%w: %d workers need the segmented algorithm", ErrInvalidConfig, b.workers)
+	}
+	if b.algorithm != AlgorithmSegmented && b.adaptive {
+		return fmt.Errorf("%w: adaptive segments need the segmented algorithm", ErrInvalidConfig)
+	}
+	if b.fanOutPolicy != FanOutBlock && b.fanOutPolicy != FanOutDrop {
+		return fmt.Errorf("%w: unknown fan out policy %d", ErrInvalidConfig, b.fanOutPolicy)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package sieve
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSieveBuilder(t *testing.T) {
+	var trace bytes.Buffer
+	builder := NewSieveBuilder().WithTrace(&trace).WithMemoryLimit(1 << 30)
+
+	sieve, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(541), sieve.NthPrime(99))
+	assert.Contains(t, trace.String(), "estimated upper bound")
+
+	built := sieve.(*PrimeNumberSieve)
+	assert.Equal(t, int64(1<<30), built.memoryLimit)
+
+	// building again gives a new sieve with its own cache
+	other, err := builder.Build()
+	assert.NoError(t, err)
+	assert.NotSame(t, built.cache, other.(*PrimeNumberSieve).cache)
+}
+
+func TestSieveBuilderDefaults(t *testing.T) {
+	sieve, err := NewSieveBuilder().Build()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), sieve.NthPrime(0))
+}
+
+func TestSieveBuilderInvalidConfig(t *testing.T) {
+	sieve, err := NewSieveBuilder().WithMemoryLimit(-1).Build()
+	assert.Nil(t, sieve)
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+
+	_, err = NewSieveBuilder().WithPrimalityCache(-1).Build()
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+
+	_, err = NewSieveBuilder().WithFanOut(8, FanOutPolicy(-1)).Build()
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+}
+
+func TestSieveBuilderAlgorithm(t *testing.T) {
+	sieve, err := NewSieveBuilder().WithAlgorithm(AlgorithmAtkin).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(541), sieve.NthPrime(99))
+	assert.Equal(t, "atkin", sieve.(*PrimeNumberSieve).newSieve().name())
+
+	sieve, err = NewSieveBuilder().WithAlgorithm(AlgorithmSegmented).WithWorkers(4).WithAdaptiveSegments(true).Build()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(541), sieve.NthPrime(99))
+
+	// a single worker is just the serial default, so any algorithm accepts it
+	_, err = NewSieveBuilder().WithAlgorithm(AlgorithmBasic).WithWorkers(1).Build()
+	assert.NoError(t, err)
+}
+
+func TestSieveBuilderInvalidAlgorithm(t *testing.T) {
+	sieve, err := NewSieveBuilder().WithAlgorithm(AlgorithmBasic).WithWorkers(4).Build()
+	assert.Nil(t, sieve)
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+
+	_, err = NewSieveBuilder().WithAlgorithm(AlgorithmAtkin).WithAdaptiveSegments(true).Build()
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+
+	_, err = NewSieveBuilder().WithAlgorithm(Algorithm(42)).Build()
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+}
+
+func TestSieveBuilderCrossCheck(t *testing.T) {
+	sieve, err := NewSieveBuilder().WithCrossCheck(true).Build()
+	assert.NoError(t, err)
+	assert.True(t, sieve.(*PrimeNumberSieve).crossCheck)
+}
+
+func TestSieveBuilderDebugVerify(t *testing.T) {
+	sieve, err := NewSieveBuilder().WithDebugVerify(true).Build()
+	assert.NoError(t, err)
+	assert.True(t, sieve.(*PrimeNumberSieve).debugVerify)
+}
+
+func TestSieveBuilderSharedCache(t *testing.T) {
+	shared := NewSharedCache()
+	builder := NewSieveBuilder().WithSharedCache(shared)
+
+	first, err := builder.Build()
+	assert.NoError(t, err)
+	second, err := builder.Build()
+	assert.NoError(t, err)
+	assert.Same(t, first.(*PrimeNumberSieve).cache, second.(*PrimeNumberSieve).cache)
+	assert.Equal(t, int64(2), shared.cache.refs)
+}
@@ -0,0 +1,289 @@
+package sieve
+
+import (
+	"context"
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// primeCache - holds the largest list of primes sieved so far so repeated queries don't have to sieve again
+type primeCache struct {
+	// hits, misses - how many queries were, or weren't, answered without sieving. Updated atomically, not under mu,
+	// so kept first in the struct where they're 64 bit aligned even on 32 bit platforms
+	hits, misses int64
+	// refs - how many open sieves use the cache, also updated atomically. See SharedCache
+	refs int64
+	mu   cacheMutex
+	// primes - every prime up to bound, in ascending order
+	primes []int64
+	bound  int64
+}
+
+// cacheMutex - guards a primeCache like a sync.Mutex, but waiting for it can be abandoned once a context is done, see
+// lockContext, so a cancelled query isn't stuck behind another query's long sieve. A buffered channel of one is the
+// lock. The zero value is unlocked
+type cacheMutex struct {
+	once sync.Once
+	sem  chan struct{}
+}
+
+// semaphore - the channel holding the lock, created on first use so the zero value works
+func (m *cacheMutex) semaphore() chan struct{} {
+	m.once.Do(func() { m.sem = make(chan struct{}, 1) })
+	return m.sem
+}
+
+// Lock - locks m, waiting for as long as it's held elsewhere
+func (m *cacheMutex) Lock() {
+	m.semaphore() <- struct{}{}
+}
+
+// lockContext - locks m, unless ctx is done first, returning ctx.Err() without the lock. An already done ctx never
+// waits, it only takes the lock if it's free right away
+func (m *cacheMutex) lockContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		select {
+		case m.semaphore() <- struct{}{}:
+			return nil
+		default:
+			return err
+		}
+	}
+	select {
+	case m.semaphore() <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Unlock - unlocks m, which must be locked
+func (m *cacheMutex) Unlock() {
+	select {
+	case <-m.semaphore():
+	default:
+		panic("sieve: unlock of unlocked cacheMutex")
+	}
+}
+
+// acquire - counts another sieve using the cache
+func (c *primeCache) acquire() {
+	atomic.AddInt64(&c.refs, 1)
+}
+
+// release - counts a sieve no longer using the cache, dropping the cached primes once no sieve is
+func (c *primeCache) release() {
+	if atomic.AddInt64(&c.refs, -1) > 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.primes, c.bound = nil, 0
+}
+
+// SharedCache - a prime cache that several sieves can share through WithSharedCache, such as the request scoped
+// sieves of a server, so primes found by one are reused by all of them. The cache is reference counted and its primes
+// released once every sieve using it has been Closed. A SharedCache is safe for concurrent use
+type SharedCache struct {
+	cache *primeCache
+}
+
+// NewSharedCache - Creates a new, empty, SharedCache
+func NewSharedCache() *SharedCache {
+	return &SharedCache{cache: &primeCache{}}
+}
+
+// Close - releases the sieve's reference to its cache. A cache of its own is released immediately, while a SharedCache
+// is only released once every sieve sharing it has been closed. Closing again does nothing, and the sieve must not
+// be used once closed
+func (s *PrimeNumberSieve) Close() {
+	s.closeOnce.Do(s.cache.release)
+}
+
+// record - counts a query as a cache hit or miss
+func (c *primeCache) record(hit bool) {
+	if hit {
+		atomic.AddInt64(&c.hits, 1)
+	} else {
+		atomic.AddInt64(&c.misses, 1)
+	}
+}
+
+// CacheStats - reports how many NthPrime, PrimePi and IsPrime queries were served from cache (hits) versus needing to
+// sieve, or for IsPrime a Miller-Rabin test (misses), for tuning how much to Prewarm
+func (s *PrimeNumberSieve) CacheStats() (hits, misses int64) {
+	return atomic.LoadInt64(&s.cache.hits), atomic.LoadInt64(&s.cache.misses)
+}
+
+// coversValue - reports whether every prime up to n is already cached
+func (s *PrimeNumberSieve) coversValue(n int64) bool {
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	return n <= s.cache.bound
+}
+
+// CacheCovers - reports whether NthPrime(n) would be served entirely from the cache without sieving
+func (s *PrimeNumberSieve) CacheCovers(n int64) bool {
+	if n < 0 {
+		return false
+	}
+
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	return n < int64(len(s.cache.primes))
+}
+
+// newSieve - creates the internal sieve used to fill the cache, configured from the sieve's options
+func (s *PrimeNumberSieve) newSieve() sieve {
+	sieveFunc := s.newSieveFunc(s)
+	if segmented, ok := sieveFunc.(*segmentedSieve); ok && s.debugVerify {
+		return &verifiedSieve{segmented: segmented}
+	}
+	return sieveFunc
+}
+
+// workerCount - how many goroutines the segmented sieve uses, one per CPU unless configured
+func (s *PrimeNumberSieve) workerCount() int {
+	if s.workers > 0 {
+		return s.workers
+	}
+	return runtime.NumCPU()
+}
+
+// Prewarm - ensures the first n primes are cached so later queries for them don't need to sieve. Does nothing when
+// n is beyond the number of primes in an int64, which NthPrime answers with 0 without sieving anyway
+func (s *PrimeNumberSieve) Prewarm(n int64) {
+	if n <= 0 || n-1 > maxPrimeIndex {
+		return
+	}
+
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	s.nthPrimeLocked(context.Background(), n-1)
+}
+
+// EnsurePrimesUpTo - ensures every prime up to value is cached, so later range and counting queries within it don't need
+// to sieve. Like Prewarm but by value rather than by index, calling it again for a covered value does nothing
+func (s *PrimeNumberSieve) EnsurePrimesUpTo(value int64) {
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	s.extendToValue(value)
+}
+
+// TouchPages - an advanced tuning knob for latency sensitive services. Ensures every prime up to n is cached, like
+// EnsurePrimesUpTo, then reads through the cached primes one memory page at a time so the whole table is resident and
+// a latency critical query that follows won't stall on page faults. Does nothing to the cache's contents
+func (s *PrimeNumberSieve) TouchPages(n int64) {
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	s.extendToValue(n)
+
+	// one read per page is enough to fault it in, keeping the sum stops the reads being optimized away
+	var sum int64
+	for i := 0; i < len(s.cache.primes); i += pageSize / 8 {
+		sum += s.cache.primes[i]
+	}
+	touchSink = sum
+}
+
+// pageSize - the memory page size TouchPages steps through the cache by, the common 4KiB
+const pageSize = 4096
+
+// touchSink - where TouchPages leaves its result so the compiler can't discard the reads
+var touchSink int64
+
+// nthPrimeLocked - returns the nth prime, extending the cache by sieving increasingly large bounds until it's found.
+// If caching the primes would exceed the memory limit the cache is left untouched and Fallback is set in the stats
+// along with the bound to stream the primes up to instead, see streamNthPrime. Sieving stops with ctx.Err() once ctx
+// is done, leaving the cache as it was. The cache lock must be held by the caller
+func (s *PrimeNumberSieve) nthPrimeLocked(ctx context.Context, nthPrime int64) (int64, SieveStats, error) {
+	stats := SieveStats{UpperBound: s.cache.bound}
+	if nthPrime < int64(len(s.cache.primes)) {
+		tracef(s.trace, "served n=%d from %d cached primes up to %d", nthPrime, len(s.cache.primes), s.cache.bound)
+		return s.cache.primes[nthPrime], stats, nil
+	}
+
+	upperBounds := EstimateUpperBound(nthPrime)
+	tracef(s.trace, "estimated upper bound %d for n=%d", upperBounds, nthPrime)
+	return s.nthPrimeFromLocked(ctx, nthPrime, upperBounds, stats)
+}
+
+// nthPrimeFromLocked - the sieving half of nthPrimeLocked, starting from upperBounds and doubling it until the nth
+// prime is found. The cache lock must be held by the caller
+func (s *PrimeNumberSieve) nthPrimeFromLocked(ctx context.Context, nthPrime, upperBounds int64, stats SieveStats) (int64, SieveStats, error) {
+	sieveFunc := s.newSieve()
+
+	// the cache already proves nothing at or below its bound is enough, so start above it
+	if upperBounds <= s.cache.bound {
+		upperBounds = doubleBound(s.cache.bound)
+		tracef(s.trace, "cache covers up to %d, raising upper bound to %d", s.cache.bound, upperBounds)
+	}
+
+	// Sieves till the upperbound and tests if the nth prime number can be found in the result
+	// If not, scale upperbound and carry on from the primes found so far
+	known, knownBound := s.cache.primes, s.cache.bound
+	for {
+		if s.exceedsMemoryLimit(upperBounds) {
+			tracef(s.trace, "caching primes up to %d would exceed the memory limit of %d bytes, streaming instead", upperBounds, s.memoryLimit)
+			stats.Fallback, stats.UpperBound = true, upperBounds
+			return 0, stats, nil
+		}
+
+		res, err := extendUntilDone(ctx, sieveFunc, known, knownBound, upperBounds)
+		stats.Passes++
+		if err != nil {
+			return 0, stats, err
+		}
+		if nthPrime < int64(len(res)) {
+			tracef(s.trace, "found n=%d within %d primes up to %d", nthPrime, len(res), upperBounds)
+			stats.UpperBound = upperBounds
+			stats.PrimesDiscovered = int64(len(res) - len(s.cache.primes))
+			s.cache.primes, s.cache.bound = res, upperBounds
+			return res[nthPrime], stats, nil
+		}
+		// every int64 has been sieved, the nth prime doesn't fit in one
+		if upperBounds == math.MaxInt64 {
+			return 0, stats, nil
+		}
+		tracef(s.trace, "n=%d not within %d primes up to %d, doubling upper bound to %d", nthPrime, len(res), upperBounds, doubleBound(upperBounds))
+		known, knownBound = res, upperBounds
+		upperBounds = doubleBound(upperBounds)
+	}
+}
+
+// extendToValue - ensures the cache holds every prime up to n, sieving up to n if it doesn't already.
+// The cache lock must be held by the caller
+func (s *PrimeNumberSieve) extendToValue(n int64) {
+	if n <= s.cache.bound {
+		return
+	}
+
+	res, _ := extendUntilDone(context.Background(), s.newSieve(), s.cache.primes, s.cache.bound, n)
+	s.cache.primes, s.cache.bound = res, n
+}
+
+// firstPrimes - returns a read-only slice of the first n primes, sieving and caching them if needed
+func (s *PrimeNumberSieve) firstPrimes(n int64) []int64 {
+	if n <= 0 {
+		return make([]int64, 0)
+	}
+
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	_, stats, _ := s.nthPrimeLocked(context.Background(), n-1)
+	if stats.Fallback {
+		// the caller needs every prime anyway, so they have to be materialized even though they won't be cached
+		return s.newSieve().sieve(stats.UpperBound)[:n]
+	}
+	return s.cache.primes[:n:n]
+}
@@ -0,0 +1,280 @@
+package sieve
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrimesDiscovered(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	sieve.Prewarm(1000)
+
+	prime, stats := sieve.NthPrimeWithStats(500)
+	assert.Equal(t, int64(3581), prime)
+	assert.Equal(t, int64(0), stats.PrimesDiscovered)
+	assert.Equal(t, 0, stats.Passes)
+
+	prime, stats = sieve.NthPrimeWithStats(2000)
+	assert.Equal(t, int64(17393), prime)
+	assert.Greater(t, stats.PrimesDiscovered, int64(0))
+	assert.Greater(t, stats.Passes, 0)
+	assert.GreaterOrEqual(t, stats.UpperBound, prime)
+
+	// the newly discovered primes are now cached too
+	_, stats = sieve.NthPrimeWithStats(2000)
+	assert.Equal(t, int64(0), stats.PrimesDiscovered)
+}
+
+func TestPrewarm(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	sieve.Prewarm(0)
+	assert.Empty(t, sieve.cache.primes)
+
+	sieve.Prewarm(1000)
+	assert.GreaterOrEqual(t, len(sieve.cache.primes), 1000)
+	assert.Equal(t, int64(7919), sieve.cache.primes[999])
+}
+
+// TestMemoizedNthPrimeConcurrent - run with -race to confirm callers sharing a sieve can extend and read its cache at once
+func TestMemoizedNthPrimeConcurrent(t *testing.T) {
+	want := (&basicSieveOfEratosthenes{}).sieve(EstimateUpperBound(10000))
+	sieve := NewPrimeNumberSieve()
+
+	// each caller walks its own interleaving of 0..10000, so the cache is extended from several at once
+	const callers = 8
+	var wg sync.WaitGroup
+	for c := 0; c < callers; c++ {
+		wg.Add(1)
+		go func(c int) {
+			defer wg.Done()
+			for i := int64(c); i <= 10000; i += int64(c + 1) {
+				assert.Equal(t, want[i], sieve.NthPrime(i), "caller=%d i=%d", c, i)
+			}
+		}(c)
+	}
+	wg.Wait()
+
+	assert.True(t, sieve.CacheCovers(10000))
+	hits, misses := sieve.CacheStats()
+	assert.Greater(t, hits, misses)
+}
+
+func TestCacheCovers(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	assert.False(t, sieve.CacheCovers(0))
+
+	sieve.Prewarm(1000)
+	assert.True(t, sieve.CacheCovers(0))
+	assert.True(t, sieve.CacheCovers(500))
+	assert.True(t, sieve.CacheCovers(999))
+	assert.False(t, sieve.CacheCovers(2000))
+	assert.False(t, sieve.CacheCovers(-1))
+
+	// a covered query doesn't need to sieve
+	_, stats := sieve.NthPrimeWithStats(500)
+	assert.Equal(t, 0, stats.Passes)
+}
+
+func TestCacheStats(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	hits, misses := sieve.CacheStats()
+	assert.Equal(t, int64(0), hits)
+	assert.Equal(t, int64(0), misses)
+
+	// the first query has to sieve, repeats within its range don't
+	sieve.NthPrime(1000)
+	sieve.NthPrime(1000)
+	sieve.NthPrime(10)
+	sieve.PrimePi(7000)
+	sieve.IsPrime(7919)
+	hits, misses = sieve.CacheStats()
+	assert.Equal(t, int64(4), hits)
+	assert.Equal(t, int64(1), misses)
+
+	// while each query beyond the cache is a miss
+	sieve.NthPrime(5000)
+	sieve.PrimePi(100000)
+	sieve.IsPrime(1000003 * 1000033)
+	hits, misses = sieve.CacheStats()
+	assert.Equal(t, int64(4), hits)
+	assert.Equal(t, int64(4), misses)
+
+	// small values are served from the small primes table
+	sieve.IsPrime(97)
+	hits, _ = sieve.CacheStats()
+	assert.Equal(t, int64(5), hits)
+}
+
+func TestEnsurePrimesUpTo(t *testing.T) {
+	var trace bytes.Buffer
+	sieve := NewPrimeNumberSieve(WithTrace(&trace))
+
+	sieve.EnsurePrimesUpTo(1000)
+	assert.True(t, sieve.coversValue(1000))
+	assert.Contains(t, trace.String(), "sieving segment")
+
+	// nothing within the value sieves again
+	trace.Reset()
+	sieve.EnsurePrimesUpTo(1000)
+	sieve.EnsurePrimesUpTo(10)
+	assert.Equal(t, int64(168), sieve.PrimePi(1000))
+	assert.Len(t, sieve.PrimesInRange(2, 1000), 168)
+	assert.Empty(t, trace.String())
+
+	hits, misses := sieve.CacheStats()
+	assert.Equal(t, int64(1), hits)
+	assert.Equal(t, int64(0), misses)
+}
+
+func TestTouchPages(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	want := NewPrimeNumberSieve().PrimesUpTo(1000000)
+
+	sieve.TouchPages(1000000)
+	assert.True(t, sieve.coversValue(1000000))
+	assert.Equal(t, want, sieve.PrimesUpTo(1000000))
+
+	// touching an already cached range leaves it as it was
+	sieve.TouchPages(1000)
+	sieve.TouchPages(-1)
+	assert.Equal(t, want, sieve.PrimesUpTo(1000000))
+	assert.Equal(t, int64(15485867), sieve.NthPrime(1000000))
+}
+
+func TestSharedCache(t *testing.T) {
+	shared := NewSharedCache()
+	first := NewPrimeNumberSieve(WithSharedCache(shared))
+	second := NewPrimeNumberSieve(WithSharedCache(shared))
+	assert.Same(t, first.cache, second.cache)
+	assert.Equal(t, int64(2), shared.cache.refs)
+
+	// primes found by one are served to the other from the cache
+	assert.Equal(t, int64(7919), first.NthPrime(999))
+	_, stats := second.NthPrimeWithStats(999)
+	assert.Equal(t, 0, stats.Passes)
+
+	// closing one leaves the cache for the other, closing it twice doesn't release the other's reference
+	first.Close()
+	first.Close()
+	assert.Equal(t, int64(1), shared.cache.refs)
+	assert.True(t, second.CacheCovers(999))
+	prime, stats := second.NthPrimeWithStats(999)
+	assert.Equal(t, int64(7919), prime)
+	assert.Equal(t, 0, stats.Passes)
+
+	// until the last is closed, releasing the primes
+	second.Close()
+	assert.Equal(t, int64(0), shared.cache.refs)
+	assert.Nil(t, shared.cache.primes)
+	assert.Zero(t, shared.cache.bound)
+
+	// a later sieve can still use the cache, starting again from empty
+	third := NewPrimeNumberSieve(WithSharedCache(shared))
+	assert.False(t, third.CacheCovers(0))
+	assert.Equal(t, int64(541), third.NthPrime(99))
+}
+
+func TestSharedCacheConcurrentClose(t *testing.T) {
+	shared := NewSharedCache()
+	keep := NewPrimeNumberSieve(WithSharedCache(shared))
+	keep.Prewarm(10000)
+
+	// sieves opened and closed concurrently, while still querying, never release the cache from under keep
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sieve := NewPrimeNumberSieve(WithSharedCache(shared))
+			assert.Equal(t, int64(104729), sieve.NthPrime(9999))
+			sieve.Close()
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int64(1), shared.cache.refs)
+	assert.True(t, keep.CacheCovers(9999))
+}
+
+func TestCloseOwnCache(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	sieve.Prewarm(1000)
+	cache := sieve.cache
+
+	sieve.Close()
+	assert.Nil(t, cache.primes)
+	assert.Equal(t, int64(0), cache.refs)
+
+	// a nil shared cache keeps the sieve's own
+	assert.Equal(t, int64(1), NewPrimeNumberSieve(WithSharedCache(nil)).cache.refs)
+}
+
+func TestDoublingExtendsPrimes(t *testing.T) {
+	var trace bytes.Buffer
+	sieve := NewPrimeNumberSieve(WithTrace(&trace))
+
+	// starting from half the estimate forces a doubling, which carries on from the primes up to the first bound
+	bound := EstimateUpperBound(100000) / 2
+	sieve.cache.mu.Lock()
+	prime, stats, _ := sieve.nthPrimeFromLocked(context.Background(), 100000, bound, SieveStats{})
+	sieve.cache.mu.Unlock()
+	assert.Equal(t, NewPrimeNumberSieve().NthPrime(100000), prime)
+	assert.Equal(t, 2, stats.Passes)
+	assert.Contains(t, trace.String(), fmt.Sprintf("extending %d primes up to %d to %d", NewPrimeNumberSieve().PrimeCount(bound), bound, 2*bound))
+
+	// the same goes for extending the cache by value
+	sieve = NewPrimeNumberSieve()
+	sieve.EnsurePrimesUpTo(1000)
+	sieve.EnsurePrimesUpTo(100000)
+	assert.Equal(t, (&segmentedSieve{}).sieve(100000), sieve.cache.primes)
+}
+
+func BenchmarkDoubling(b *testing.B) {
+	// the 10^6th prime from half its estimate, so it's only found after doubling
+	bound := EstimateUpperBound(1000000) / 2
+
+	b.Run("restart", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			s := &segmentedSieve{}
+			s.sieve(bound)
+			s.sieve(2 * bound)
+		}
+	})
+	b.Run("extend", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			sieve := NewPrimeNumberSieve()
+			sieve.cache.mu.Lock()
+			sieve.nthPrimeFromLocked(context.Background(), 1000000, bound, SieveStats{})
+			sieve.cache.mu.Unlock()
+		}
+	})
+}
+
+func TestCacheMutex(t *testing.T) {
+	var m cacheMutex
+	m.Lock()
+
+	// held, so waiting is abandoned with the context
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, m.lockContext(ctx))
+
+	m.Unlock()
+	assert.NoError(t, m.lockContext(context.Background()))
+	m.Unlock()
+
+	// a done context only takes the lock when it's free, without waiting
+	done, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NoError(t, m.lockContext(done))
+	assert.Equal(t, context.Canceled, m.lockContext(done))
+	m.Unlock()
+	assert.Panics(t, m.Unlock)
+}
@@ -0,0 +1,30 @@
+package sieve
+
+import "errors"
+
+// Errors returned by the package. Every error returned by a PrimeNumberSieve or SieveBuilder wraps one of these,
+// so callers can match them with errors.Is regardless of any added context, with two exceptions passed through as is:
+// ctx.Err() once the context given to a method such as NthPrimeCtx is done, and errors from the io.Writer given to
+// SaveTo or GenerateGoSource
+var (
+	// ErrNegativeIndex - a prime was requested at an index below 0
+	ErrNegativeIndex = errors.New("sieve: negative prime index")
+	// ErrIndexTooLarge - the requested index needs a sieve bound that cannot be represented as an int64
+	ErrIndexTooLarge = errors.New("sieve: prime index too large")
+	// ErrMemoryLimitExceeded - answering the query would need more memory than the sieve is allowed to use
+	ErrMemoryLimitExceeded = errors.New("sieve: memory limit exceeded")
+	// ErrNoPrimeFound - no prime satisfies the query, such as the nth prime of a progression holding fewer than n+1
+	ErrNoPrimeFound = errors.New("sieve: no prime found")
+	// ErrOverflow - the result of the query doesn't fit in an int64
+	ErrOverflow = errors.New("sieve: int64 overflow")
+	// ErrCorruptTable - a serialized prime table is malformed, truncated or fails verification
+	ErrCorruptTable = errors.New("sieve: corrupt prime table")
+	// ErrCorruptPosition - a saved PrimeGenerator position is malformed or truncated
+	ErrCorruptPosition = errors.New("sieve: corrupt generator position")
+	// ErrInvalidConfig - a sieve was configured with invalid, or incompatible, settings
+	ErrInvalidConfig = errors.New("sieve: invalid configuration")
+	// ErrInvalidName - a name given to generate code with isn't a valid Go identifier
+	ErrInvalidName = errors.New("sieve: invalid Go identifier")
+	// ErrCrossCheckFailed - the sieve and Miller-Rabin disagreed on whether a number is prime, see WithCrossCheck
+	ErrCrossCheckFailed = errors.New("sieve: cross check failed")
+)
@@ -0,0 +1,69 @@
+package sieve
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// failingWriter - an io.Writer whose every write fails with err
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+func TestErrors(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	for _, c := range []struct {
+		want error
+		call func() error
+	}{
+		{ErrNegativeIndex, func() error { _, err := sieve.PrimeAt(-1); return err }},
+		{ErrIndexTooLarge, func() error { _, err := sieve.PrimeAt(maxPrimeIndex + 1); return err }},
+		{ErrMemoryLimitExceeded, func() error {
+			_, err := NewPrimeNumberSieve(WithMemoryLimit(1 << 20)).NthPrimeBig(big.NewInt(maxPrimeIndex + 1))
+			return err
+		}},
+		{ErrNoPrimeFound, func() error { _, err := sieve.PrimeInProgression(4, 6, 0); return err }},
+		{ErrOverflow, func() error { _, err := sieve.SumFirstNPrimes(math.MaxInt64); return err }},
+		{ErrCorruptTable, func() error { return sieve.LoadFrom(strings.NewReader("not a table")) }},
+		{ErrCorruptPosition, func() error { _, err := sieve.NewGeneratorFromPosition([]byte("PPOS")); return err }},
+		{ErrInvalidConfig, func() error { _, err := NewSieveBuilder().WithMemoryLimit(-1).Build(); return err }},
+		{ErrInvalidName, func() error { return sieve.GenerateGoSource(io.Discard, "1primes", 10) }},
+		{ErrCrossCheckFailed, func() error {
+			checked := NewPrimeNumberSieve(WithCrossCheck(true))
+			checked.probablePrime = func(int64) bool { return true }
+			_, err := checked.IsPrimeChecked(4)
+			return err
+		}},
+	} {
+		err := c.call()
+		assert.True(t, errors.Is(err, c.want), "want %v, got %v", c.want, err)
+	}
+}
+
+func TestErrorsPassedThrough(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// context errors are returned as is, rather than wrapping a sentinel
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := sieve.NthPrimeCtx(ctx, 1000000)
+	assert.Equal(t, context.Canceled, err)
+
+	// as are the writer's errors
+	failed := errors.New("disk full")
+	sieve.Prewarm(100)
+	assert.True(t, errors.Is(sieve.SaveTo(failingWriter{failed}), failed))
+	assert.True(t, errors.Is(sieve.GenerateGoSource(failingWriter{failed}, "primes", 10), failed))
+}
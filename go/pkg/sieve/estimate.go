@@ -0,0 +1,157 @@
+package sieve
+
+import "math"
+
+// NthPrimeWithinError - returns the nth prime when relErr is 0, otherwise a fast estimate within relErr of it,
+// skipping sieving entirely. The estimate inverts Riemann's R(x) ~ pi(x) with Newton's method,
+// starting from the asymptotic expansion of p_n. When relErr is tighter than the estimate can promise, or n is too
+// small for the estimate to be accurate, the exact prime is returned instead. Negative n returns 0 like NthPrime
+func (s *PrimeNumberSieve) NthPrimeWithinError(n int64, relErr float64) int64 {
+	if n < 0 {
+		return 0
+	}
+	if relErr < estimateRelErr || n < estimateMinIndex {
+		return s.NthPrime(n)
+	}
+	return int64(math.Round(estimateNthPrime(n)))
+}
+
+// EstimateUpperBound - returns a bound the nth (0-based) prime is guaranteed not to exceed, so a single sieve up to it
+// finds the prime without having to double the bound and sieve again, or a caller can size its own buffers with it.
+// Uses Rosser's theorem, p_k < k(ln k + ln ln k) for the kth prime counting from 1 once k >= 6, rounded up:
+// https://en.wikipedia.org/wiki/Prime_number_theorem#Approximations_for_the_nth_prime_number
+// Indices whose bound doesn't fit in an int64 return math.MaxInt64
+func EstimateUpperBound(n int64) int64 {
+	if n < 5 {
+		return 20 // handles n <= 5 better since log is small for these
+	}
+
+	k := float64(n + 1)
+	bound := math.Ceil(k * (math.Log(k) + math.Log(math.Log(k))))
+	if bound >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	return int64(bound)
+}
+
+// doubleBound - doubles an upper bound for another sieve pass, saturating at math.MaxInt64 rather than wrapping negative
+func doubleBound(bound int64) int64 {
+	if bound > math.MaxInt64/2 {
+		return math.MaxInt64
+	}
+	return bound * 2
+}
+
+// the estimate is within estimateRelErr of the true prime for every index from estimateMinIndex up
+const (
+	estimateRelErr   = 0.01
+	estimateMinIndex = 1000
+)
+
+// estimateNthPrime - estimates the nth (0-based) prime by solving R(x) = n+1 with a few Newton steps
+func estimateNthPrime(n int64) float64 {
+	k := float64(n + 1) // the nth prime is the (n+1)th counting from 1
+
+	// Cipolla's asymptotic expansion: p_k ~ k(ln k + ln ln k - 1 + (ln ln k - 2)/ln k)
+	lnK := math.Log(k)
+	lnLnK := math.Log(lnK)
+	x := k * (lnK + lnLnK - 1 + (lnLnK-2)/lnK)
+
+	// R'(x) is close to 1/ln(x), so each Newton step is x - (R(x) - k) * ln(x)
+	for i := 0; i < 4; i++ {
+		x -= (riemannR(x) - k) * math.Log(x)
+	}
+	return x
+}
+
+// riemannR - Riemann's R(x) = sum of mu(m)/m * li(x^(1/m)), a far closer approximation of pi(x) than li(x) alone.
+// Terms stop once x^(1/m) drops below 2, where they no longer contribute meaningfully
+func riemannR(x float64) float64 {
+	// mobius - the Mobius function for m up to 64, enough for any float64 x
+	mobius := [...]int{0, 1, -1, -1, 0, -1, 1, -1, 0, 0, 1, -1, 0, -1, 1, 1, 0, -1, 0, -1, 0, 1, 1, -1, 0, 0, 1, 0, 0,
+		-1, -1, -1, 0, 1, 1, 1, 0, -1, 1, 1, 0, -1, -1, -1, 0, 0, 1, -1, 0, 0, 0, 1, 0, -1, 0, 1, 0, 1, 1, -1, 0, -1, 1, 0, 0}
+
+	sum := 0.0
+	for m := 1; m < len(mobius); m++ {
+		root := math.Pow(x, 1/float64(m))
+		if root < 2 {
+			break
+		}
+		if mobius[m] != 0 {
+			sum += float64(mobius[m]) / float64(m) * logIntegral(root)
+		}
+	}
+	return sum
+}
+
+// logIntegral - li(x) for x > 1, using Ramanujan's rapidly converging series
+func logIntegral(x float64) float64 {
+	const eulerGamma = 0.57721566490153286061
+
+	lnX := math.Log(x)
+	sum, term, inner := 0.0, 1.0, 0.0
+	for n := 1; n < 200; n++ {
+		// term = (-1)^(n-1) (ln x)^n / (n! 2^(n-1))
+		term *= lnX / float64(n)
+		if n > 1 {
+			term /= -2
+		}
+		// inner = sum of 1/(2k+1) for k up to (n-1)/2
+		if n%2 == 1 {
+			inner += 1 / float64(n)
+		}
+		delta := term * inner
+		sum += delta
+		if math.Abs(delta) < 1e-17*math.Abs(sum) {
+			break
+		}
+	}
+	return eulerGamma + math.Log(lnX) + math.Sqrt(x)*sum
+}
+
+// Chebyshev's bounds, 0.92 x/ln x <= pi(x) <= 1.11 x/ln x. The lower bound holds for every x >= 11, the upper only
+// from chebyshevUpperFrom on, below which Rosser and Schoenfeld's pi(x) < 1.25506 x/ln x is used instead
+const (
+	chebyshevMinX      = 11
+	chebyshevLower     = 0.92
+	chebyshevUpper     = 1.11
+	chebyshevUpperFrom = 61732
+	rosserUpper        = 1.25506
+)
+
+// withinChebyshevBounds - reports whether pi, a computed count of the primes <= x, falls within Chebyshev's bounds,
+// catching a grossly miscounting sieve. Always true below chebyshevMinX where the bounds don't apply
+func withinChebyshevBounds(x, pi int64) bool {
+	if x < chebyshevMinX {
+		return true
+	}
+
+	upper := chebyshevUpper
+	if x < chebyshevUpperFrom {
+		upper = rosserUpper
+	}
+
+	approx := float64(x) / math.Log(float64(x))
+	return float64(pi) >= chebyshevLower*approx && float64(pi) <= upper*approx
+}
+
+// LocalPrimeDensity - returns the fraction of numbers in [x-window, x+window] that are prime, an empirical estimate of
+// the density 1/ln(x) the prime number theorem predicts near x. The window is clamped to [2, math.MaxInt64], and is
+// empty, with a density of 0, when window is negative or x+window is below 2
+func (s *PrimeNumberSieve) LocalPrimeDensity(x, window int64) float64 {
+	if window < 0 {
+		return 0
+	}
+
+	lo, hi := x-window, x+window
+	if x < math.MinInt64+window || lo < 2 {
+		lo = 2
+	}
+	if x > math.MaxInt64-window {
+		hi = math.MaxInt64
+	}
+	if hi < lo {
+		return 0
+	}
+	return float64(s.CountPrimesInRange(lo, hi)) / float64(hi-lo+1)
+}
@@ -0,0 +1,188 @@
+package sieve
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNthPrimeWithinError(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// no error allowed is exactly NthPrime
+	for _, n := range []int64{0, 19, 99, 500, 2000, 100000} {
+		assert.Equal(t, sieve.NthPrime(n), sieve.NthPrimeWithinError(n, 0), "n=%d", n)
+	}
+
+	known := map[int64]int64{
+		1000:      7927,
+		2000:      17393,
+		1000000:   15485867,
+		10000000:  179424691,
+		100000000: 2038074751,
+	}
+	for n, prime := range known {
+		estimate := sieve.NthPrimeWithinError(n, 0.05)
+		assert.InEpsilon(t, float64(prime), float64(estimate), 0.05, "n=%d", n)
+	}
+
+	// indices too small to estimate accurately are computed exactly
+	assert.Equal(t, int64(71), sieve.NthPrimeWithinError(19, 0.05))
+	assert.Equal(t, int64(0), sieve.NthPrimeWithinError(-1, 0.05))
+}
+
+func TestLogIntegral(t *testing.T) {
+	assert.InDelta(t, 1.045163780117, logIntegral(2), 1e-9)
+	assert.InDelta(t, 177.6096580, logIntegral(1000), 1e-6)
+	assert.InDelta(t, 78627.54915946, logIntegral(1e6), 1e-6)
+}
+
+func TestRiemannR(t *testing.T) {
+	// R(x) tracks pi(x) closely, pi(10^6) = 78498 and pi(10^9) = 50847534
+	assert.InDelta(t, 78498, riemannR(1e6), 30)
+	assert.InDelta(t, 50847534, riemannR(1e9), 100)
+	assert.False(t, math.IsNaN(riemannR(2)))
+}
+
+// TestUpperBoundEstimateNeverUndershoots - guards against estimator changes that would silently reintroduce extra
+// sieving passes, by checking the initial bound holds the true nth prime across a sample of indices
+func TestUpperBoundEstimateNeverUndershoots(t *testing.T) {
+	// every index whose prime is below 1.3 million, sieved once
+	primes := (&segmentedSieve{}).sieve(1300000)
+	for n := range primes {
+		assert.GreaterOrEqual(t, EstimateUpperBound(int64(n)), primes[n], "n=%d", n)
+	}
+
+	// and a sample of known primes beyond it, without sieving up to them
+	known := map[int64]int64{
+		1000000:   15485867,
+		10000000:  179424691,
+		100000000: 2038074751,
+	}
+	for n, prime := range known {
+		assert.GreaterOrEqual(t, EstimateUpperBound(n), prime, "n=%d", n)
+	}
+
+	// so a fresh sieve only ever needs one pass
+	for _, n := range []int64{0, 5, 6, 7, 19, 99, 500, 986, 2000, 1000000} {
+		_, stats := NewPrimeNumberSieve().NthPrimeWithStats(n)
+		assert.Equal(t, 1, stats.Passes, "n=%d", n)
+	}
+}
+
+func TestWithinChebyshevBounds(t *testing.T) {
+	// every true count up to a couple of million is within the bounds
+	primes := (&segmentedSieve{}).sieve(2000000)
+	var pi int64
+	for x := int64(1); x <= 2000000; x++ {
+		for pi < int64(len(primes)) && primes[pi] <= x {
+			pi++
+		}
+		if !withinChebyshevBounds(x, pi) {
+			assert.Fail(t, "true count outside the bounds", "pi(%d) = %d", x, pi)
+			break
+		}
+	}
+
+	// while a gross miscount is flagged
+	assert.False(t, withinChebyshevBounds(1000000, 78498/2))
+	assert.False(t, withinChebyshevBounds(1000000, 78498*2))
+	assert.False(t, withinChebyshevBounds(100, 0))
+	assert.True(t, withinChebyshevBounds(10, 0))
+}
+
+func TestEstimateUpperBound(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	for _, n := range []int64{0, 1, 4, 5, 6, 10, 99, 1000, 12345, 99999, 1000000} {
+		assert.GreaterOrEqual(t, EstimateUpperBound(n), sieve.NthPrime(n), "n=%d", n)
+	}
+	assert.Equal(t, int64(20), EstimateUpperBound(-1))
+
+	// bounds beyond an int64 saturate rather than wrapping around
+	assert.Equal(t, int64(math.MaxInt64), EstimateUpperBound(maxPrimeIndex))
+	assert.Equal(t, int64(math.MaxInt64), EstimateUpperBound(math.MaxInt64-1))
+}
+
+func TestEstimateUpperBoundSaturation(t *testing.T) {
+	// the old n*int64(ln n) estimate wraps negative by the time int64(ln n) reaches 40
+	oldOverflow := int64(math.Exp(40)) + 1
+	assert.Less(t, oldOverflow*int64(math.Log(float64(oldOverflow))), int64(0))
+
+	prev := EstimateUpperBound(oldOverflow - 1000)
+	for n := oldOverflow - 1000; n <= oldOverflow+1000; n++ {
+		bound := EstimateUpperBound(n)
+		assert.GreaterOrEqual(t, bound, prev, "n=%d", n)
+		prev = bound
+	}
+
+	// find an index whose bound first saturates; float rounding near 2^63 may flicker either side of it, but
+	// never wraps negative or drops far
+	lo, hi := oldOverflow, int64(math.MaxInt64)
+	for lo < hi-1 {
+		mid := lo + (hi-lo)/2
+		if EstimateUpperBound(mid) < math.MaxInt64 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	assert.Equal(t, int64(math.MaxInt64), EstimateUpperBound(hi))
+
+	floor := EstimateUpperBound(hi - 1000)
+	assert.Greater(t, floor, int64(math.MaxInt64-math.MaxInt64/1000))
+	for n := hi - 1000; n <= hi+1000; n++ {
+		assert.GreaterOrEqual(t, EstimateUpperBound(n), floor, "n=%d", n)
+	}
+}
+
+func TestLocalPrimeDensity(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// 1/ln(1000) ~ 0.145, while [900, 1100] holds 30 primes in 201 numbers
+	assert.InDelta(t, 1/math.Log(1000), sieve.LocalPrimeDensity(1000, 100), 0.01)
+	assert.InDelta(t, 30.0/201, sieve.LocalPrimeDensity(1000, 100), 1e-12)
+	assert.InDelta(t, 1/math.Log(1e9), sieve.LocalPrimeDensity(1e9, 100000), 0.001)
+
+	// the lower bound is clamped to 2, so [2, 12] holds 5 primes in 11 numbers
+	assert.InDelta(t, 5.0/11, sieve.LocalPrimeDensity(5, 7), 1e-12)
+	assert.InDelta(t, 1, sieve.LocalPrimeDensity(2, 0), 1e-12)
+
+	assert.Zero(t, sieve.LocalPrimeDensity(1000, -1))
+	assert.Zero(t, sieve.LocalPrimeDensity(-100, 10))
+	assert.Zero(t, sieve.LocalPrimeDensity(4, 0))
+
+	// and the upper bound to math.MaxInt64, so [2^63-1001, 2^63-1] holds 23 primes in 1001 numbers
+	assert.InDelta(t, 23.0/1001, sieve.LocalPrimeDensity(math.MaxInt64, 1000), 1e-12)
+	assert.InDelta(t, 1/math.Log(math.MaxInt64), sieve.LocalPrimeDensity(math.MaxInt64, 100000), 0.002)
+}
+
+func TestDoubleBound(t *testing.T) {
+	assert.Equal(t, int64(40), doubleBound(20))
+	assert.Equal(t, int64(math.MaxInt64-1), doubleBound(math.MaxInt64/2))
+
+	// where doubling used to wrap negative it now saturates
+	assert.Equal(t, int64(math.MaxInt64), doubleBound(math.MaxInt64/2+1))
+	assert.Equal(t, int64(math.MaxInt64), doubleBound(math.MaxInt64))
+}
+
+func TestNthPrimeOverflowingIndices(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// indices whose bound would overflow return immediately instead of sieving a wrapped bound
+	for _, n := range []int64{maxPrimeIndex + 1, math.MaxInt64 / 2, math.MaxInt64} {
+		prime, stats := sieve.NthPrimeWithStats(n)
+		assert.Equal(t, int64(0), prime, "n=%d", n)
+		assert.Equal(t, 0, stats.Passes, "n=%d", n)
+	}
+
+	// while the largest valid index still gets a positive bound to fall back to streaming with
+	sieve = NewPrimeNumberSieve(WithMemoryLimit(1 << 20))
+	sieve.cache.mu.Lock()
+	_, stats, _ := sieve.nthPrimeLocked(context.Background(), maxPrimeIndex)
+	sieve.cache.mu.Unlock()
+	assert.True(t, stats.Fallback)
+	assert.Equal(t, int64(math.MaxInt64), stats.UpperBound)
+}
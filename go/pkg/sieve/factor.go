@@ -0,0 +1,253 @@
+package sieve
+
+import (
+	"math"
+	"sort"
+)
+
+// SmallestFactor - returns the smallest prime factor of n, which is n itself when n is prime.
+// Values of n below 2 have no prime factors and return 0
+func (s *PrimeNumberSieve) SmallestFactor(n int64) int64 {
+	if n < 2 {
+		return 0
+	}
+
+	// a composite n always has a prime factor no larger than sqrt(n). Primes are sieved a window at a time
+	// so a small factor is found without first sieving everything up to sqrt(n)
+	limit := isqrt(n)
+	for lo := int64(2); lo <= limit; lo += trialDivisionWindow {
+		hi := lo + trialDivisionWindow - 1
+		if hi > limit {
+			hi = limit
+		}
+		for _, p := range s.PrimesInRange(lo, hi) {
+			if n%p == 0 {
+				return p
+			}
+		}
+	}
+	return n
+}
+
+// trialDivisionWindow - how many candidate divisors are sieved at a time when searching for a factor
+const trialDivisionWindow = 1 << 15
+
+// IsPrimePower - reports whether n = p^k for some prime p and k >= 1, returning p and k when it is
+func (s *PrimeNumberSieve) IsPrimePower(n int64) (prime int64, exp int, ok bool) {
+	p := s.SmallestFactor(n)
+	if p == 0 {
+		return 0, 0, false
+	}
+
+	// n is a prime power only if nothing but p remains after dividing out every factor of p
+	for ; n%p == 0; n /= p {
+		exp++
+	}
+	if n != 1 {
+		return 0, 0, false
+	}
+	return p, exp, true
+}
+
+// DivisorCount - returns the number of positive divisors of n, the product of (k+1) over each prime power p^k in its
+// factorization. Values of n below 1 return 0
+func (s *PrimeNumberSieve) DivisorCount(n int64) int64 {
+	if n < 1 {
+		return 0
+	}
+
+	// divide out each prime factor as it's found, so the trial division limit shrinks along with n
+	count := int64(1)
+	for lo := int64(2); lo <= isqrt(n); lo += trialDivisionWindow {
+		hi := lo + trialDivisionWindow - 1
+		if limit := isqrt(n); hi > limit {
+			hi = limit
+		}
+		for _, p := range s.PrimesInRange(lo, hi) {
+			if p > isqrt(n) {
+				break
+			}
+			exp := int64(0)
+			for ; n%p == 0; n /= p {
+				exp++
+			}
+			count *= exp + 1
+		}
+	}
+
+	// whatever remains above 1 is a single prime factor larger than the square root
+	if n > 1 {
+		count *= 2
+	}
+	return count
+}
+
+// Factorize - returns the prime factors of n in ascending order, each repeated by its multiplicity so their product is
+// n, e.g. 360 gives 2, 2, 2, 3, 3, 5. Trial divides by the primes up to sqrt(n), sieved a window at a time, stopping
+// as soon as what remains is prime, which is then the one factor larger than the square root. Values of n below 2 have
+// no prime factors and return an empty slice
+func (s *PrimeNumberSieve) Factorize(n int64) []int64 {
+	factors := make([]int64, 0)
+	if n < 2 {
+		return factors
+	}
+
+	// divide out each prime factor as it's found, so the trial division limit shrinks along with n
+	for lo := int64(2); lo <= isqrt(n) && !millerRabin(n); lo += trialDivisionWindow {
+		hi := lo + trialDivisionWindow - 1
+		if limit := isqrt(n); hi > limit {
+			hi = limit
+		}
+		for _, p := range s.PrimesInRange(lo, hi) {
+			if p > isqrt(n) {
+				break
+			}
+			for ; n%p == 0; n /= p {
+				factors = append(factors, p)
+			}
+		}
+	}
+
+	if n > 1 {
+		factors = append(factors, n)
+	}
+	return factors
+}
+
+// KthPrimeFactor - returns the kth smallest distinct prime factor of n counting from 1, so 2, 3 and 5 for k = 1, 2 and 3
+// of 60 = 2^2 * 3 * 5. ok is false when n has fewer than k distinct prime factors, or k is below 1
+func (s *PrimeNumberSieve) KthPrimeFactor(n int64, k int) (factor int64, ok bool) {
+	if k < 1 {
+		return 0, false
+	}
+
+	factors := s.distinctPrimeFactors(n)
+	if k > len(factors) {
+		return 0, false
+	}
+	return factors[k-1], true
+}
+
+// Radical - returns rad(n), the product of the distinct prime factors of n and so the largest squarefree divisor of n,
+// e.g. rad(360) = 2 * 3 * 5 = 30. Values of n up to 1 have no prime factors and return 1, the empty product
+func (s *PrimeNumberSieve) Radical(n int64) int64 {
+	rad := int64(1)
+	for _, p := range s.distinctPrimeFactors(n) {
+		rad *= p
+	}
+	return rad
+}
+
+// PrimeSignature - returns the exponents of the prime powers in n's factorization in ascending order, so 72 = 2^3 * 3^2
+// gives 2, 3. Numbers sharing a signature share arithmetic properties such as their divisor count. Values of n below 2
+// have no prime factors and return an empty slice
+func (s *PrimeNumberSieve) PrimeSignature(n int64) []int {
+	signature := make([]int, 0)
+
+	// Factorize repeats each prime by its multiplicity in ascending order, so each run is one exponent
+	factors := s.Factorize(n)
+	for i := 0; i < len(factors); {
+		j := i
+		for j < len(factors) && factors[j] == factors[i] {
+			j++
+		}
+		signature = append(signature, j-i)
+		i = j
+	}
+
+	sort.Ints(signature)
+	return signature
+}
+
+// ABCQuality - returns the quality ln(c)/ln(rad(abc)) of an abc triple, positive a and b with gcd(a, b) = 1 and
+// a + b = c, which the abc conjecture says exceeds 1 + e for only finitely many triples. (1, 8, 9) gives
+// ln(9)/ln(6) ~ 1.226. Values that aren't an abc triple return 0
+func (s *PrimeNumberSieve) ABCQuality(a, b, c int64) float64 {
+	if a < 1 || b < 1 || c < 1 || c-a != b || gcd(a, b) != 1 {
+		return 0
+	}
+
+	// a, b and c are pairwise coprime, so rad(abc) = rad(a) * rad(b) * rad(c) and summing the logs avoids
+	// overflowing the product
+	return math.Log(float64(c)) / (math.Log(float64(s.Radical(a))) + math.Log(float64(s.Radical(b))) + math.Log(float64(s.Radical(c))))
+}
+
+// OmegaUpTo - returns omega(n), the number of distinct prime factors of n, for every n in [0, limit] indexed by n.
+// Sieved like the primes themselves, each prime adding one to every one of its multiples, with omega(0) = omega(1) = 0.
+// A uint8 holds any omega in an int64, the product of the first 16 primes already overflows one
+func (s *PrimeNumberSieve) OmegaUpTo(limit int64) []uint8 {
+	if limit < 0 {
+		return make([]uint8, 0)
+	}
+
+	omega := make([]uint8, limit+1)
+	primes := s.PrimesUpToShared(limit)
+	for i := 0; i < primes.Len(); i++ {
+		p := primes.At(i)
+		// stepping from limit down keeps the multiples from overflowing near the top of the int64 range
+		for m := limit - limit%p; m >= p; m -= p {
+			omega[m]++
+		}
+	}
+	return omega
+}
+
+// CountByOmega - returns how many integers in [1, limit] have exactly k distinct prime factors, so k = 1 counts the
+// prime powers and k = 0 just 1. Built on OmegaUpTo, so it needs a byte of memory for every integer up to limit
+func (s *PrimeNumberSieve) CountByOmega(limit int64, k int) int64 {
+	if k < 0 {
+		return 0
+	}
+
+	var count int64
+	for n, omega := range s.OmegaUpTo(limit) {
+		if n >= 1 && int(omega) == k {
+			count++
+		}
+	}
+	return count
+}
+
+// HighlyCompositeNumbers - returns every number <= limit with more divisors than any smaller positive integer,
+// in ascending order
+func (s *PrimeNumberSieve) HighlyCompositeNumbers(limit int64) []int64 {
+	res := make([]int64, 0)
+
+	var most int64
+	for n := int64(1); n <= limit && n > 0; n++ {
+		if count := s.DivisorCount(n); count > most {
+			most = count
+			res = append(res, n)
+		}
+	}
+	return res
+}
+
+// FactorialFactorization - returns the prime factorization of n! as a map of prime to exponent, without computing n!.
+// By Legendre's formula the exponent of p is the sum of floor(n/p^k) over k >= 1. Values of n below 2 return an empty map
+func (s *PrimeNumberSieve) FactorialFactorization(n int64) map[int64]int {
+	res := make(map[int64]int)
+
+	primes := s.PrimesUpToShared(n)
+	for i := 0; i < primes.Len(); i++ {
+		p := primes.At(i)
+		res[p] = int(legendreExponent(n, p))
+	}
+	return res
+}
+
+// FactorialTrailingZeros - returns the number of trailing zeros of n! in base 10. Every zero needs a factor of 2 and 5,
+// and 2s are more plentiful, so this is the exponent of 5 in n!. Negative values of n return 0
+func (s *PrimeNumberSieve) FactorialTrailingZeros(n int64) int64 {
+	return legendreExponent(n, 5)
+}
+
+// legendreExponent - the exponent of the prime p in n!, the sum of floor(n/p^k) over k >= 1.
+// Dividing repeatedly sums floor(n/p), floor(n/p^2), ... without ever computing p^k, so it can't overflow
+func legendreExponent(n, p int64) int64 {
+	var exp int64
+	for q := n / p; q > 0; q /= p {
+		exp += q
+	}
+	return exp
+}
@@ -0,0 +1,254 @@
+package sieve
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSmallestFactor(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, int64(0), sieve.SmallestFactor(-4))
+	assert.Equal(t, int64(0), sieve.SmallestFactor(1))
+	assert.Equal(t, int64(2), sieve.SmallestFactor(2))
+	assert.Equal(t, int64(2), sieve.SmallestFactor(360))
+	assert.Equal(t, int64(7), sieve.SmallestFactor(49))
+	assert.Equal(t, int64(7919), sieve.SmallestFactor(7919))
+	assert.Equal(t, int64(999979), sieve.SmallestFactor(999983*999979))
+}
+
+func TestIsPrimePower(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assertPrimePower := func(n, prime int64, exp int, ok bool) {
+		p, e, isPower := sieve.IsPrimePower(n)
+		assert.Equal(t, ok, isPower, "n=%d", n)
+		if ok {
+			assert.Equal(t, prime, p, "n=%d", n)
+			assert.Equal(t, exp, e, "n=%d", n)
+		}
+	}
+
+	assertPrimePower(8, 2, 3, true)
+	assertPrimePower(7, 7, 1, true)
+	assertPrimePower(81, 3, 4, true)
+	assertPrimePower(1<<62, 2, 62, true)
+	assertPrimePower(12, 0, 0, false)
+	assertPrimePower(1, 0, 0, false)
+	assertPrimePower(0, 0, 0, false)
+	assertPrimePower(-8, 0, 0, false)
+}
+
+func TestDivisorCount(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	expected := map[int64]int64{-4: 0, 0: 0, 1: 1, 2: 2, 12: 6, 36: 9, 360: 24, 7919: 2, 1 << 20: 21, 720720: 240}
+	for n, want := range expected {
+		assert.Equal(t, want, sieve.DivisorCount(n), "n=%d", n)
+	}
+
+	// a large prime factor left over after trial division
+	assert.Equal(t, int64(4), sieve.DivisorCount(2*1000003))
+}
+
+func TestFactorize(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, []int64{2, 2, 2, 3, 3, 5}, sieve.Factorize(360))
+	for _, n := range []int64{-12, 0, 1} {
+		assert.Equal(t, []int64{}, sieve.Factorize(n), "n=%d", n)
+	}
+
+	// primes are their own single factor, however large
+	for _, p := range []int64{2, 541, 999983, 2038074751, 9223372036854775783} {
+		assert.Equal(t, []int64{p}, sieve.Factorize(p), "p=%d", p)
+	}
+
+	// prime powers
+	assert.Equal(t, []int64{2, 2, 2, 2, 2, 2, 2, 2, 2, 2}, sieve.Factorize(1024))
+	assert.Equal(t, []int64{7919, 7919}, sieve.Factorize(7919*7919))
+	threes := make([]int64, 39)
+	for i := range threes {
+		threes[i] = 3
+	}
+	assert.Equal(t, threes, sieve.Factorize(4052555153018976267)) // 3^39
+
+	// a large factor left over once the small ones are divided out, and semiprimes of two large primes
+	assert.Equal(t, []int64{2, 3, 2038074751}, sieve.Factorize(6*2038074751))
+	assert.Equal(t, []int64{999979, 999983}, sieve.Factorize(999983*999979))
+	assert.Equal(t, []int64{1000003, 1000033}, sieve.Factorize(1000003*1000033))
+
+	// the factors always multiply back to n
+	for n := int64(2); n <= 5000; n++ {
+		product := int64(1)
+		for _, p := range sieve.Factorize(n) {
+			assert.True(t, sieve.IsPrime(p), "factor %d of %d", p, n)
+			product *= p
+		}
+		if !assert.Equal(t, n, product) {
+			break
+		}
+	}
+}
+
+func TestKthPrimeFactor(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	for k, want := range map[int]int64{1: 2, 2: 3, 3: 5} {
+		factor, ok := sieve.KthPrimeFactor(60, k)
+		assert.True(t, ok, "k=%d", k)
+		assert.Equal(t, want, factor, "k=%d", k)
+	}
+
+	// repeated factors only count once
+	factor, ok := sieve.KthPrimeFactor(1024, 1)
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), factor)
+	factor, ok = sieve.KthPrimeFactor(6*2038074751, 3)
+	assert.True(t, ok)
+	assert.Equal(t, int64(2038074751), factor)
+
+	for _, c := range []struct {
+		n int64
+		k int
+	}{{60, 4}, {60, 0}, {60, -1}, {1024, 2}, {1, 1}, {-60, 1}} {
+		_, ok := sieve.KthPrimeFactor(c.n, c.k)
+		assert.False(t, ok, "n=%d k=%d", c.n, c.k)
+	}
+}
+
+func TestRadical(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	for n, want := range map[int64]int64{
+		-8: 1, 0: 1, 1: 1, 2: 2, 7: 7, 12: 6, 72: 6, 360: 30, 1024: 2, 7919 * 7919: 7919, 6 * 2038074751: 6 * 2038074751,
+	} {
+		assert.Equal(t, want, sieve.Radical(n), "n=%d", n)
+	}
+
+	// squarefree numbers are their own radical
+	for _, n := range []int64{30, 210, 999983 * 999979} {
+		assert.Equal(t, n, sieve.Radical(n), "n=%d", n)
+	}
+}
+
+func TestPrimeSignature(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, []int{1, 2}, sieve.PrimeSignature(12))
+	assert.Equal(t, []int{2, 3}, sieve.PrimeSignature(72))
+	assert.Equal(t, []int{1}, sieve.PrimeSignature(7919))
+	assert.Equal(t, []int{1, 1, 1}, sieve.PrimeSignature(30))
+	assert.Equal(t, []int{10}, sieve.PrimeSignature(1024))
+	assert.Equal(t, []int{1, 2}, sieve.PrimeSignature(7919*7919*2))
+	assert.Equal(t, []int{}, sieve.PrimeSignature(1))
+	assert.Equal(t, []int{}, sieve.PrimeSignature(-12))
+
+	// the divisor count is the product of one more than each exponent
+	for n := int64(1); n <= 1000; n++ {
+		count := int64(1)
+		for _, exp := range sieve.PrimeSignature(n) {
+			count *= int64(exp + 1)
+		}
+		assert.Equal(t, sieve.DivisorCount(n), count, "n=%d", n)
+	}
+}
+
+func TestABCQuality(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// rad(1 * 8 * 9) = rad(72) = 6
+	assert.InDelta(t, math.Log(9)/math.Log(6), sieve.ABCQuality(1, 8, 9), 1e-12)
+	assert.InDelta(t, 1.226, sieve.ABCQuality(1, 8, 9), 1e-3)
+	assert.InDelta(t, 1, sieve.ABCQuality(1, 1, 2), 1e-12)
+	assert.Less(t, sieve.ABCQuality(3, 4, 7), 1.0)
+
+	// the highest quality triple known, 2 + 3^10 * 109 = 23^5
+	assert.InDelta(t, 1.6299, sieve.ABCQuality(2, 6436341, 6436343), 1e-4)
+
+	// not abc triples
+	assert.Zero(t, sieve.ABCQuality(2, 4, 6))
+	assert.Zero(t, sieve.ABCQuality(1, 8, 10))
+	assert.Zero(t, sieve.ABCQuality(0, 9, 9))
+	assert.Zero(t, sieve.ABCQuality(-1, 10, 9))
+	assert.Zero(t, sieve.ABCQuality(1, math.MaxInt64, math.MinInt64))
+}
+
+func TestOmegaUpTo(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, []uint8{0, 0, 1, 1, 1, 1, 2, 1, 1, 1, 2, 1, 2}, sieve.OmegaUpTo(12))
+	assert.Equal(t, []uint8{0}, sieve.OmegaUpTo(0))
+	assert.Empty(t, sieve.OmegaUpTo(-1))
+
+	// matches counting the distinct prime factors of each n
+	omega := sieve.OmegaUpTo(10000)
+	for n := int64(1); n <= 10000; n++ {
+		assert.Equal(t, len(sieve.distinctPrimeFactors(n)), int(omega[n]), "n=%d", n)
+	}
+}
+
+func TestCountByOmega(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// the prime powers up to 30 are 2, 3, 4, 5, 7, 8, 9, 11, 13, 16, 17, 19, 23, 25, 27 and 29
+	assert.Equal(t, int64(16), sieve.CountByOmega(30, 1))
+	// with two are 6, 10, 12, 14, 15, 18, 20, 21, 22, 24, 26 and 28
+	assert.Equal(t, int64(12), sieve.CountByOmega(30, 2))
+	// leaving 30 = 2 * 3 * 5 with three, and 1 with none
+	assert.Equal(t, int64(1), sieve.CountByOmega(30, 3))
+	assert.Equal(t, int64(1), sieve.CountByOmega(30, 0))
+	assert.Equal(t, int64(0), sieve.CountByOmega(30, 4))
+
+	assert.Equal(t, int64(0), sieve.CountByOmega(0, 0))
+	assert.Equal(t, int64(0), sieve.CountByOmega(30, -1))
+	assert.Equal(t, int64(0), sieve.CountByOmega(-30, 1))
+}
+
+func TestHighlyCompositeNumbers(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, []int64{1, 2, 4, 6, 12, 24, 36, 48, 60, 120, 180, 240, 360, 720, 840, 1260, 1680, 2520, 5040,
+		7560, 10080}, sieve.HighlyCompositeNumbers(10080))
+	assert.Equal(t, []int64{1, 2, 4, 6, 12, 24, 36, 48, 60}, sieve.HighlyCompositeNumbers(100))
+	assert.Equal(t, []int64{}, sieve.HighlyCompositeNumbers(0))
+}
+
+func TestFactorialFactorization(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, map[int64]int{2: 8, 3: 4, 5: 2, 7: 1}, sieve.FactorialFactorization(10))
+	assert.Equal(t, map[int64]int{}, sieve.FactorialFactorization(1))
+	assert.Equal(t, map[int64]int{}, sieve.FactorialFactorization(-3))
+	assert.Equal(t, map[int64]int{2: 1}, sieve.FactorialFactorization(2))
+
+	// the factorization multiplies back out to n!
+	for _, n := range []int64{5, 20, 57} {
+		product := big.NewInt(1)
+		for p, exp := range sieve.FactorialFactorization(n) {
+			product.Mul(product, new(big.Int).Exp(big.NewInt(p), big.NewInt(int64(exp)), nil))
+		}
+		assert.Equal(t, new(big.Int).MulRange(1, n).String(), product.String(), "n=%d", n)
+	}
+}
+
+func TestFactorialTrailingZeros(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, int64(6), sieve.FactorialTrailingZeros(25))
+	assert.Equal(t, int64(2), sieve.FactorialTrailingZeros(10))
+	assert.Equal(t, int64(0), sieve.FactorialTrailingZeros(4))
+	assert.Equal(t, int64(0), sieve.FactorialTrailingZeros(-5))
+	assert.Equal(t, int64(249998), sieve.FactorialTrailingZeros(1000000))
+
+	// matches counting the zeros of n! directly
+	for n := int64(0); n <= 60; n++ {
+		digits := new(big.Int).MulRange(1, n).String()
+		zeros := int64(len(digits) - len(strings.TrimRight(digits, "0")))
+		assert.Equal(t, zeros, sieve.FactorialTrailingZeros(n), "n=%d", n)
+	}
+}
@@ -0,0 +1,107 @@
+package sieve
+
+import "math"
+
+// LongestCompositeRun - returns where the longest run of consecutive composite numbers <= limit starts, and its length.
+// This is the largest gap between consecutive primes minus one, except that a run may also end at limit itself.
+// The earliest run wins a tie, and a limit with no composites (below 4) returns 0, 0
+func (s *PrimeNumberSieve) LongestCompositeRun(limit int64) (start, length int64) {
+	primes := s.PrimesUpToShared(limit)
+
+	for i := 1; i < primes.Len(); i++ {
+		if run := primes.At(i) - primes.At(i-1) - 1; run > length {
+			start, length = primes.At(i-1)+1, run
+		}
+	}
+
+	// the numbers after the last prime are composite all the way up to limit
+	if primes.Len() > 0 {
+		last := primes.At(primes.Len() - 1)
+		if run := limit - last; run > length {
+			start, length = last+1, run
+		}
+	}
+
+	return start, length
+}
+
+// GapRecord - a maximal prime gap, one larger than every gap between smaller consecutive primes
+type GapRecord struct {
+	// Gap - the difference between After and the next prime
+	Gap int64
+	// After - the prime the gap follows
+	After int64
+	// Merit - Gap / ln(After), how large the gap is relative to the average gap between primes near After
+	Merit float64
+}
+
+// GapRecordsTable - returns every maximal prime gap where both primes are <= limit, in ascending order
+func (s *PrimeNumberSieve) GapRecordsTable(limit int64) []GapRecord {
+	res := make([]GapRecord, 0)
+	primes := s.PrimesUpToShared(limit)
+
+	var largest int64
+	for i := 1; i < primes.Len(); i++ {
+		after := primes.At(i - 1)
+		if gap := primes.At(i) - after; gap > largest {
+			largest = gap
+			res = append(res, GapRecord{Gap: gap, After: after, Merit: float64(gap) / math.Log(float64(after))})
+		}
+	}
+	return res
+}
+
+// GapStatistics - returns the mean and population variance of the gaps between consecutive primes in [lo, hi], along
+// with how many gaps there are. The statistics are updated as each gap is found by Welford's method, so no list of
+// gaps is kept and the result is stable however many there are. A range with fewer than two primes has no gaps
+func (s *PrimeNumberSieve) GapStatistics(lo, hi int64) (mean, variance float64, count int64) {
+	primes := s.PrimesInRange(lo, hi)
+
+	var m2 float64 // sum of squared differences from the running mean
+	for i := 1; i < len(primes); i++ {
+		gap := float64(primes[i] - primes[i-1])
+		count++
+		delta := gap - mean
+		mean += delta / float64(count)
+		m2 += delta * (gap - mean)
+	}
+
+	if count == 0 {
+		return 0, 0, 0
+	}
+	return mean, m2 / float64(count), count
+}
+
+// TwinPrimes - returns every twin prime pair (p, p+2) with low <= p <= high in ascending order. Only p has to be in the
+// window, so a pair straddling high is included. Like PrimesInRange only the window is sieved
+func (s *PrimeNumberSieve) TwinPrimes(low, high int64) [][2]int64 {
+	res := make([][2]int64, 0)
+	if high < low {
+		return res
+	}
+
+	// sieve two past high for the partner of a p at the top of the window, without overflowing
+	top := high
+	if top <= math.MaxInt64-2 {
+		top += 2
+	}
+	primes := s.PrimesInRange(low, top)
+	for i := 1; i < len(primes) && primes[i-1] <= high; i++ {
+		if primes[i]-primes[i-1] == 2 {
+			res = append(res, [2]int64{primes[i-1], primes[i]})
+		}
+	}
+	return res
+}
+
+// MaxPrimeGap - returns the largest gap between consecutive primes within [low, high] and the prime it starts at, the
+// earliest one on a tie. A window with fewer than two primes has no gap and returns 0, 0
+func (s *PrimeNumberSieve) MaxPrimeGap(low, high int64) (gap, start int64) {
+	primes := s.PrimesInRange(low, high)
+	for i := 1; i < len(primes); i++ {
+		if g := primes[i] - primes[i-1]; g > gap {
+			gap, start = g, primes[i-1]
+		}
+	}
+	return gap, start
+}
@@ -0,0 +1,124 @@
+package sieve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLongestCompositeRun(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assertRun := func(limit, start, length int64) {
+		s, l := sieve.LongestCompositeRun(limit)
+		assert.Equal(t, start, s, "start for limit=%d", limit)
+		assert.Equal(t, length, l, "length for limit=%d", limit)
+	}
+
+	// 90..96 sits between 89 and 97
+	assertRun(100, 90, 7)
+	assertRun(3, 0, 0)
+	assertRun(4, 4, 1)
+	assertRun(10, 8, 3)
+	// the trailing run 114..120 is cut short by limit, before that 90..96 is still the longest
+	assertRun(120, 90, 7)
+	// but 114..126 between 113 and 127 is longer once it's complete
+	assertRun(127, 114, 13)
+	// 1327 is followed by 1361, the first gap of more than 30
+	assertRun(2000, 1328, 33)
+}
+
+func TestGapRecordsTable(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	records := sieve.GapRecordsTable(1000)
+	gaps, after := make([]int64, 0), make([]int64, 0)
+	for _, record := range records {
+		gaps, after = append(gaps, record.Gap), append(after, record.After)
+	}
+	assert.Equal(t, []int64{1, 2, 4, 6, 8, 14, 18, 20}, gaps)
+	assert.Equal(t, []int64{2, 3, 7, 23, 89, 113, 523, 887}, after)
+
+	// merit is the gap over the log of the prime it follows
+	assert.InDelta(t, 1.4427, records[0].Merit, 1e-4)
+	assert.InDelta(t, 1.8205, records[1].Merit, 1e-4)
+	assert.InDelta(t, 2.0556, records[2].Merit, 1e-4)
+	assert.InDelta(t, 1.9136, records[3].Merit, 1e-4)
+	assert.InDelta(t, 2.9615, records[5].Merit, 1e-4)
+
+	// a gap only counts once the prime ending it is within the limit
+	assert.Len(t, sieve.GapRecordsTable(112), 5)
+	assert.Len(t, sieve.GapRecordsTable(127), 6)
+	assert.Empty(t, sieve.GapRecordsTable(2))
+}
+
+func TestGapStatistics(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// the primes up to 30 have gaps 1, 2, 2, 4, 2, 4, 2, 4, 6, summing to 27 with squared deviations from 3 of 20
+	mean, variance, count := sieve.GapStatistics(0, 30)
+	assert.Equal(t, int64(9), count)
+	assert.InDelta(t, 3, mean, 1e-12)
+	assert.InDelta(t, 20.0/9, variance, 1e-12)
+
+	// only gaps between primes inside the range count, 11 to 19 has gaps 2, 4, 2
+	mean, variance, count = sieve.GapStatistics(10, 20)
+	assert.Equal(t, int64(3), count)
+	assert.InDelta(t, 8.0/3, mean, 1e-12)
+	assert.InDelta(t, 8.0/9, variance, 1e-12)
+
+	// the mean gap is the span of the primes over the number of gaps
+	primes := sieve.PrimesInRange(1000000, 2000000)
+	mean, _, count = sieve.GapStatistics(1000000, 2000000)
+	assert.Equal(t, int64(len(primes)-1), count)
+	assert.InDelta(t, float64(primes[len(primes)-1]-primes[0])/float64(count), mean, 1e-9)
+
+	// fewer than two primes have no gaps
+	for _, r := range [][2]int64{{0, 2}, {24, 28}, {30, 10}} {
+		mean, variance, count = sieve.GapStatistics(r[0], r[1])
+		assert.Equal(t, int64(0), count, "range %v", r)
+		assert.Zero(t, mean, "range %v", r)
+		assert.Zero(t, variance, "range %v", r)
+	}
+}
+
+func TestTwinPrimes(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, [][2]int64{{3, 5}, {5, 7}, {11, 13}, {17, 19}, {29, 31}}, sieve.TwinPrimes(0, 30))
+	assert.Equal(t, [][2]int64{{5, 7}, {11, 13}}, sieve.TwinPrimes(5, 16))
+	assert.Equal(t, [][2]int64{{1000037, 1000039}}, sieve.TwinPrimes(1000000, 1000100))
+
+	assert.Equal(t, [][2]int64{}, sieve.TwinPrimes(30, 0))
+	assert.Equal(t, [][2]int64{}, sieve.TwinPrimes(-10, 2))
+	assert.Equal(t, [][2]int64{}, sieve.TwinPrimes(24, 28))
+
+	// there are 35 pairs below 1000
+	assert.Len(t, sieve.TwinPrimes(0, 1000), 35)
+}
+
+func TestMaxPrimeGap(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// the maximal gaps of 34 after 1327 and 36 after 9551
+	gap, start := sieve.MaxPrimeGap(0, 2000)
+	assert.Equal(t, int64(34), gap)
+	assert.Equal(t, int64(1327), start)
+	gap, start = sieve.MaxPrimeGap(0, 10000)
+	assert.Equal(t, int64(36), gap)
+	assert.Equal(t, int64(9551), start)
+
+	// only gaps with both primes in the window count, and the earliest of equal gaps wins
+	gap, start = sieve.MaxPrimeGap(2, 30)
+	assert.Equal(t, int64(6), gap)
+	assert.Equal(t, int64(23), start)
+	gap, start = sieve.MaxPrimeGap(7, 20)
+	assert.Equal(t, int64(4), gap)
+	assert.Equal(t, int64(7), start)
+
+	for _, r := range [][2]int64{{20, 10}, {24, 28}, {0, 2}, {1000000, 1000004}} {
+		gap, start = sieve.MaxPrimeGap(r[0], r[1])
+		assert.Zero(t, gap, "range %v", r)
+		assert.Zero(t, start, "range %v", r)
+	}
+}
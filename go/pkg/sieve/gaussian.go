@@ -0,0 +1,51 @@
+package sieve
+
+import "sort"
+
+// GaussianPrimes - returns the Gaussian primes a+bi with norm a^2 + b^2 <= limit as {a, b} pairs, ordered by norm then
+// by a. Each prime has four associates, itself times 1, i, -1 and -i, so only the one with a > 0 and b >= 0 is
+// returned. Every Gaussian prime divides a rational prime p, found by rotating the question onto the rational primes:
+//
+//	p = 2 ramifies, 2 = -i(1+i)^2, giving 1+i
+//	p = 3 (mod 4) stays prime, giving p itself with norm p^2
+//	p = 1 (mod 4) splits, p = a^2 + b^2 = (a+bi)(a-bi), giving a+bi and b+ai
+func (s *PrimeNumberSieve) GaussianPrimes(limit int64) [][2]int64 {
+	res := make([][2]int64, 0)
+
+	primes := s.PrimesUpToShared(limit)
+	for i := 0; i < primes.Len(); i++ {
+		switch p := primes.At(i); {
+		case p == 2:
+			res = append(res, [2]int64{1, 1})
+		case p%4 == 3:
+			if p <= isqrt(limit) {
+				res = append(res, [2]int64{p, 0})
+			}
+		default:
+			a, b := twoSquares(p)
+			res = append(res, [2]int64{a, b}, [2]int64{b, a})
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		ni, nj := res[i][0]*res[i][0]+res[i][1]*res[i][1], res[j][0]*res[j][0]+res[j][1]*res[j][1]
+		return ni < nj || ni == nj && res[i][0] < res[j][0]
+	})
+	return res
+}
+
+// twoSquares - returns a > b > 0 with a^2 + b^2 = p, for a prime p = 1 (mod 4), by the Hermite-Serret algorithm.
+// x^2 = -1 (mod p) is found from a quadratic non-residue c as c^((p-1)/4), then the euclidean algorithm on p and x
+// reaches a and b as the first two remainders below sqrt(p)
+func twoSquares(p int64) (a, b int64) {
+	c := int64(2)
+	for powmod(c, (p-1)/2, p) != p-1 {
+		c++
+	}
+
+	a, b = p, powmod(c, (p-1)/4, p)
+	for limit := isqrt(p); a > limit; {
+		a, b = b, a%b
+	}
+	return a, b
+}
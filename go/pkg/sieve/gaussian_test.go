@@ -0,0 +1,60 @@
+package sieve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGaussianPrimes(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// 3 stays prime with norm 9, while 5 = (2+i)(2-i) splits into 2+i and its conjugate's associate 1+2i
+	primes := sieve.GaussianPrimes(13)
+	assert.Equal(t, [][2]int64{{1, 1}, {1, 2}, {2, 1}, {3, 0}, {2, 3}, {3, 2}}, primes)
+	assert.NotContains(t, primes, [2]int64{5, 0})
+	assert.NotContains(t, sieve.GaussianPrimes(100), [2]int64{5, 0})
+
+	// matches checking every a+bi directly: a Gaussian integer off the axes is prime iff its norm is a rational prime,
+	// while one on an axis is prime iff it's a rational prime = 3 (mod 4)
+	want := make([][2]int64, 0)
+	for norm := int64(1); norm <= 10000; norm++ {
+		for a := int64(1); a*a <= norm; a++ {
+			b := isqrt(norm - a*a)
+			if a*a+b*b != norm {
+				continue
+			}
+			if b == 0 && sieve.IsPrime(a) && a%4 == 3 || b > 0 && sieve.IsPrime(norm) {
+				want = append(want, [2]int64{a, b})
+			}
+		}
+	}
+	assert.Equal(t, want, sieve.GaussianPrimes(10000))
+
+	assert.Empty(t, sieve.GaussianPrimes(1))
+	assert.Empty(t, sieve.GaussianPrimes(-5))
+}
+
+func TestTwoSquares(t *testing.T) {
+	assert.Equal(t, [2]int64{2, 1}, pair(twoSquares(5)))
+	assert.Equal(t, [2]int64{3, 2}, pair(twoSquares(13)))
+
+	for _, p := range (&segmentedSieve{}).sieve(1000000) {
+		if p%4 != 1 {
+			continue
+		}
+		a, b := twoSquares(p)
+		if a*a+b*b != p || b >= a || b < 1 {
+			assert.Fail(t, "not a sum of two squares", "p=%d a=%d b=%d", p, a, b)
+		}
+	}
+
+	// primes near the top of the int64 range decompose without overflowing
+	a, b := twoSquares(9223372036854775549)
+	assert.Equal(t, uint64(9223372036854775549), uint64(a)*uint64(a)+uint64(b)*uint64(b))
+}
+
+// pair - gathers two results into an array for comparing
+func pair(a, b int64) [2]int64 {
+	return [2]int64{a, b}
+}
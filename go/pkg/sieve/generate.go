@@ -0,0 +1,49 @@
+package sieve
+
+import (
+	"bufio"
+	"fmt"
+	"go/token"
+	"io"
+	"strconv"
+)
+
+// generatedPerLine - how many primes GenerateGoSource writes on each line of the slice literal
+const generatedPerLine = 10
+
+// GenerateGoSource - writes a gofmt formatted Go source file for package pkgName to w, declaring
+// var FirstPrimes = []int64{2, 3, 5, ...} holding the first n primes, so a project can embed a fixed table without
+// depending on this package at runtime. Returns an error wrapping ErrNegativeIndex if n is negative, or ErrInvalidName
+// if pkgName isn't a valid package name
+func (s *PrimeNumberSieve) GenerateGoSource(w io.Writer, pkgName string, n int64) error {
+	if n < 0 {
+		return fmt.Errorf("%w: cannot generate the first %d primes", ErrNegativeIndex, n)
+	}
+	if !token.IsIdentifier(pkgName) || pkgName == "_" {
+		return fmt.Errorf("%w: %q is not a valid package name", ErrInvalidName, pkgName)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "// Code generated by sieve.GenerateGoSource; DO NOT EDIT.\n\npackage %s\n\n", pkgName)
+	fmt.Fprintf(bw, "// FirstPrimes - the first %d primes in ascending order\n", n)
+	if n == 0 {
+		bw.WriteString("var FirstPrimes = []int64{}\n")
+		return bw.Flush()
+	}
+
+	bw.WriteString("var FirstPrimes = []int64{")
+	buf := make([]byte, 0, 20)
+	for i, p := range s.firstPrimes(n) {
+		if i%generatedPerLine == 0 {
+			bw.WriteString("\n\t")
+		} else {
+			bw.WriteByte(' ')
+		}
+		bw.Write(strconv.AppendInt(buf[:0], p, 10))
+		bw.WriteByte(',')
+	}
+	bw.WriteString("\n}\n")
+
+	// a bufio.Writer keeps the first error, returning it here
+	return bw.Flush()
+}
@@ -0,0 +1,62 @@
+package sieve
+
+import (
+	"bytes"
+	"errors"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// generatedPrimes - parses a file written by GenerateGoSource, returning its package name and FirstPrimes
+func generatedPrimes(t *testing.T, src []byte) (string, []int64) {
+	file, err := parser.ParseFile(token.NewFileSet(), "primes.go", src, 0)
+	if !assert.NoError(t, err) {
+		return "", nil
+	}
+
+	primes := make([]int64, 0)
+	spec := file.Decls[0].(*ast.GenDecl).Specs[0].(*ast.ValueSpec)
+	assert.Equal(t, "FirstPrimes", spec.Names[0].Name)
+	for _, elt := range spec.Values[0].(*ast.CompositeLit).Elts {
+		p, err := strconv.ParseInt(elt.(*ast.BasicLit).Value, 10, 64)
+		assert.NoError(t, err)
+		primes = append(primes, p)
+	}
+	return file.Name.Name, primes
+}
+
+func TestGenerateGoSource(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	for _, n := range []int64{0, 1, 9, 10, 11, 1000} {
+		var buf bytes.Buffer
+		assert.NoError(t, sieve.GenerateGoSource(&buf, "primes", n), "n=%d", n)
+
+		pkg, primes := generatedPrimes(t, buf.Bytes())
+		assert.Equal(t, "primes", pkg)
+		assert.Len(t, primes, int(n), "n=%d", n)
+		assert.Equal(t, sieve.firstPrimes(n), primes, "n=%d", n)
+
+		// already formatted, so go generate output won't churn under gofmt
+		formatted, err := format.Source(buf.Bytes())
+		assert.NoError(t, err)
+		assert.Equal(t, string(formatted), buf.String(), "n=%d", n)
+	}
+}
+
+func TestGenerateGoSourceInvalid(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	var buf bytes.Buffer
+
+	assert.True(t, errors.Is(sieve.GenerateGoSource(&buf, "primes", -1), ErrNegativeIndex))
+	for _, name := range []string{"", "_", "1primes", "my-primes", "func"} {
+		assert.True(t, errors.Is(sieve.GenerateGoSource(&buf, name, 10), ErrInvalidName), "name=%q", name)
+	}
+	assert.Zero(t, buf.Len())
+}
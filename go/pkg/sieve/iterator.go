@@ -0,0 +1,129 @@
+package sieve
+
+import (
+	"encoding/binary"
+	"fmt"
+	"iter"
+	"math"
+)
+
+// iteratorWindow - how many numbers Primes sieves at a time
+const iteratorWindow = 1 << 16
+
+// Primes - returns an iterator over the primes in ascending order, from 2 and without end (short of the largest prime in
+// an int64), for consuming primes lazily and stopping whenever a condition is met with break. The window of numbers
+// being yielded from is sieved separately from the cache, so memory stays bounded by the window however far it runs
+func (s *PrimeNumberSieve) Primes() iter.Seq[int64] {
+	return s.NewGenerator().Primes()
+}
+
+// PrimeGenerator - yields the primes in ascending order a window at a time like Primes, but as a value whose position
+// can be saved with SavePosition and resumed with NewGeneratorFromPosition, say across process restarts.
+// A PrimeGenerator is not safe for concurrent use
+type PrimeGenerator struct {
+	sieve *PrimeNumberSieve
+	// window - the primes of the window being yielded from, window[next] is the next prime
+	window []int64
+	next   int
+	// lo - where the window after this one starts, 0 once the last window, up to the largest int64, has been sieved
+	lo int64
+}
+
+// positionMagic - identifies a saved PrimeGenerator position, followed by its format version
+const (
+	positionMagic   = "PPOS"
+	positionVersion = 1
+)
+
+// NewGenerator - Creates a PrimeGenerator starting from 2
+func (s *PrimeNumberSieve) NewGenerator() *PrimeGenerator {
+	return &PrimeGenerator{sieve: s, lo: 2}
+}
+
+// NewGeneratorFromPosition - Creates a PrimeGenerator resuming from a position saved by SavePosition, so its first prime
+// is the one that would have followed. Malformed or truncated positions return an error wrapping ErrCorruptPosition
+func (s *PrimeNumberSieve) NewGeneratorFromPosition(position []byte) (*PrimeGenerator, error) {
+	if len(position) < len(positionMagic)+1 || string(position[:len(positionMagic)]) != positionMagic {
+		return nil, fmt.Errorf("%w: not a generator position", ErrCorruptPosition)
+	}
+	if version := position[len(positionMagic)]; version != positionVersion {
+		return nil, fmt.Errorf("%w: unsupported version %d", ErrCorruptPosition, version)
+	}
+
+	lo, size := binary.Uvarint(position[len(positionMagic)+1:])
+	if size <= 0 || len(position) != len(positionMagic)+1+size {
+		return nil, fmt.Errorf("%w: malformed resume value", ErrCorruptPosition)
+	}
+	if lo > math.MaxInt64 || lo == 1 {
+		return nil, fmt.Errorf("%w: cannot resume from %d", ErrCorruptPosition, lo)
+	}
+	return &PrimeGenerator{sieve: s, lo: int64(lo)}, nil
+}
+
+// Next - returns the next prime, or false once every prime in an int64 has been yielded
+func (g *PrimeGenerator) Next() (int64, bool) {
+	for g.next == len(g.window) {
+		if g.lo == 0 {
+			return 0, false
+		}
+
+		// stop at the last int64 rather than stepping past it
+		hi := int64(math.MaxInt64)
+		if g.lo <= math.MaxInt64-iteratorWindow {
+			hi = g.lo + iteratorWindow - 1
+		}
+		g.window, g.next = g.sieve.PrimesInRange(g.lo, hi), 0
+		g.lo = hi + 1
+		if hi == math.MaxInt64 {
+			g.lo = 0
+		}
+	}
+
+	p := g.window[g.next]
+	g.next++
+	return p, true
+}
+
+// Primes - returns an iterator over the primes the generator has still to yield, advancing it as they're consumed
+func (g *PrimeGenerator) Primes() iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		for p, ok := g.Next(); ok; p, ok = g.Next() {
+			if !yield(p) {
+				return
+			}
+		}
+	}
+}
+
+// SavePosition - encodes where the generator is up to for NewGeneratorFromPosition. Only the value to resume sieving
+// from is kept, the window is sieved again on resuming, so a position is a handful of bytes:
+//
+//	"PPOS", a version byte
+//	uvarint resume value, the next prime yielded is the first at or above it, 0 once every prime has been yielded
+func (g *PrimeGenerator) SavePosition() []byte {
+	resume := g.lo
+	if g.next < len(g.window) {
+		resume = g.window[g.next]
+	}
+	return binary.AppendUvarint(append([]byte(positionMagic), positionVersion), uint64(resume))
+}
+
+// PrimesDescendingFrom - returns an iterator over the primes <= start in descending order, ending after 2. Windows are
+// sieved one at a time downward from start as they're reached, so a large start only ever sieves what's yielded
+func (s *PrimeNumberSieve) PrimesDescendingFrom(start int64) iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		for hi := start; hi >= 2; {
+			lo := int64(2)
+			if hi-lo >= iteratorWindow {
+				lo = hi - iteratorWindow + 1
+			}
+			window := s.PrimesInRange(lo, hi)
+			for i := len(window) - 1; i >= 0; i-- {
+				if !yield(window[i]) {
+					return
+				}
+			}
+			hi = lo - 1
+		}
+	}
+}
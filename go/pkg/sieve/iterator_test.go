@@ -0,0 +1,184 @@
+package sieve
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrimes(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	want := NewPrimeNumberSieve().PrimesUpTo(2000000)
+
+	// across many windows, stopping part way through one
+	got := make([]int64, 0)
+	for p := range sieve.Primes() {
+		if p > 2000000 {
+			break
+		}
+		got = append(got, p)
+	}
+	assert.Equal(t, want, got)
+
+	// without caching anything it yielded
+	assert.Zero(t, sieve.cache.bound)
+}
+
+func TestPrimesStopsOnFirstValue(t *testing.T) {
+	calls := 0
+	sieve := NewPrimeNumberSieve()
+	sieve.Primes()(func(p int64) bool {
+		calls++
+		assert.Equal(t, int64(2), p)
+		return false
+	})
+	assert.Equal(t, 1, calls)
+
+	// a fresh iterator starts from 2 again
+	for p := range sieve.Primes() {
+		assert.Equal(t, int64(2), p)
+		break
+	}
+}
+
+func TestPrimesDescendingFrom(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	got := make([]int64, 0)
+	for p := range sieve.PrimesDescendingFrom(30) {
+		if len(got) == 5 {
+			break
+		}
+		got = append(got, p)
+	}
+	assert.Equal(t, []int64{29, 23, 19, 17, 13}, got)
+
+	// run to the end across several windows, it's every prime in reverse
+	want := sieve.PrimesUpTo(300000)
+	got = make([]int64, 0)
+	for p := range sieve.PrimesDescendingFrom(300000) {
+		got = append(got, p)
+	}
+	for i, j := 0, len(got)-1; i < j; i, j = i+1, j-1 {
+		got[i], got[j] = got[j], got[i]
+	}
+	assert.Equal(t, want, got)
+
+	// a start that's prime is included, and below 2 there's nothing
+	for p := range sieve.PrimesDescendingFrom(7919) {
+		assert.Equal(t, int64(7919), p)
+		break
+	}
+	for range sieve.PrimesDescendingFrom(1) {
+		assert.Fail(t, "no primes below 2")
+	}
+}
+
+func TestPrimesDescendingFromLargeStart(t *testing.T) {
+	// only the top window is sieved, rather than everything below start
+	got := make([]int64, 0)
+	for p := range NewPrimeNumberSieve().PrimesDescendingFrom(1000000000000) {
+		got = append(got, p)
+		if len(got) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []int64{999999999989, 999999999961, 999999999959}, got)
+
+	// including from the very top of an int64
+	got = got[:0]
+	for p := range NewPrimeNumberSieve().PrimesDescendingFrom(math.MaxInt64) {
+		got = append(got, p)
+		if len(got) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []int64{9223372036854775783, 9223372036854775643, 9223372036854775549}, got)
+}
+
+func TestGeneratorResumesFromPosition(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	want := sieve.PrimesUpTo(2000000)
+
+	generator := sieve.NewGenerator()
+	for i := 0; i < 1000; i++ {
+		p, ok := generator.Next()
+		assert.True(t, ok)
+		assert.Equal(t, want[i], p)
+	}
+	position := generator.SavePosition()
+	assert.Less(t, len(position), 16)
+
+	// a new generator on a new sieve, as after a restart, carries on with the 1001st prime
+	resumed, err := NewPrimeNumberSieve().NewGeneratorFromPosition(position)
+	assert.NoError(t, err)
+	p, _ := resumed.Next()
+	assert.Equal(t, want[1000], p)
+
+	// and keeps going across windows
+	got := []int64{p}
+	for p := range resumed.Primes() {
+		if p > 2000000 {
+			break
+		}
+		got = append(got, p)
+	}
+	assert.Equal(t, want[1000:], got)
+
+	// saving at a window boundary, and from a fresh generator, resume where they should
+	resumed, _ = sieve.NewGeneratorFromPosition(sieve.NewGenerator().SavePosition())
+	p, _ = resumed.Next()
+	assert.Equal(t, int64(2), p)
+	generator = sieve.NewGenerator()
+	generator.Next()
+	for generator.next < len(generator.window) {
+		generator.Next()
+	}
+	resumed, _ = sieve.NewGeneratorFromPosition(generator.SavePosition())
+	p, _ = resumed.Next()
+	assert.Equal(t, sieve.NextPrime(2+iteratorWindow-1), p)
+}
+
+func TestGeneratorEnds(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// resumed a couple of windows below math.MaxInt64, it runs through the last window and stops without stepping past
+	start := int64(math.MaxInt64 - 2*iteratorWindow - 100)
+	generator, err := sieve.NewGeneratorFromPosition(binary.AppendUvarint([]byte("PPOS\x01"), uint64(start)))
+	assert.NoError(t, err)
+	var primes []int64
+	for p := range generator.Primes() {
+		primes = append(primes, p)
+	}
+	assert.Equal(t, sieve.PrimesInRange(start, math.MaxInt64), primes)
+	assert.Equal(t, int64(9223372036854775783), primes[len(primes)-1])
+	_, ok := generator.Next()
+	assert.False(t, ok)
+
+	// an exhausted generator stays exhausted when resumed
+	resumed, err := sieve.NewGeneratorFromPosition(generator.SavePosition())
+	assert.NoError(t, err)
+	_, ok = resumed.Next()
+	assert.False(t, ok)
+}
+
+func TestGeneratorCorruptPosition(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	position := sieve.NewGenerator().SavePosition()
+
+	for _, bad := range [][]byte{
+		nil,
+		position[:len(position)-1],
+		append(append([]byte{}, position...), 0),
+		[]byte("PRMS\x01\x02"),
+		[]byte("PPOS\x02\x02"),
+		[]byte("PPOS\x01\x01"),
+		[]byte("PPOS\x01\x80\x80\x80\x80\x80\x80\x80\x80\x80\x01"),
+	} {
+		_, err := sieve.NewGeneratorFromPosition(bad)
+		assert.True(t, errors.Is(err, ErrCorruptPosition), "position=%q", bad)
+	}
+}
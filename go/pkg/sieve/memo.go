@@ -0,0 +1,60 @@
+package sieve
+
+import (
+	"container/list"
+	"sync"
+)
+
+// primalityCache - a fixed size, least recently used, memo of IsPrime results, safe for concurrent use
+type primalityCache struct {
+	mu   sync.Mutex
+	size int
+	// order - most recently used entry at the front, the next to be evicted at the back
+	order   *list.List
+	entries map[int64]*list.Element
+	// hits, misses - how many lookups were, or weren't, answered from the cache
+	hits, misses int64
+}
+
+// primalityEntry - a single memoized IsPrime result
+type primalityEntry struct {
+	n     int64
+	prime bool
+}
+
+// newPrimalityCache - Creates a primalityCache holding at most size results
+func newPrimalityCache(size int) *primalityCache {
+	return &primalityCache{size: size, order: list.New(), entries: make(map[int64]*list.Element, size)}
+}
+
+// get - returns the memoized result for n, if any, marking it as the most recently used
+func (c *primalityCache) get(n int64) (prime bool, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[n]
+	if !ok {
+		c.misses++
+		return false, false
+	}
+	c.hits++
+	c.order.MoveToFront(e)
+	return e.Value.(primalityEntry).prime, true
+}
+
+// put - memoizes the result for n, evicting the least recently used result once the cache is full
+func (c *primalityCache) put(n int64, prime bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.entries[n]; ok {
+		c.order.MoveToFront(e)
+		return
+	}
+	if c.order.Len() >= c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(primalityEntry).n)
+	}
+	c.entries[n] = c.order.PushFront(primalityEntry{n: n, prime: prime})
+}
@@ -0,0 +1,69 @@
+package sieve
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrimalityCacheHits(t *testing.T) {
+	sieve := NewPrimeNumberSieve(WithPrimalityCache(8))
+
+	assert.True(t, sieve.IsPrime(1000003))
+	assert.Equal(t, int64(0), sieve.primality.hits)
+	assert.Equal(t, int64(1), sieve.primality.misses)
+
+	for i := 0; i < 5; i++ {
+		assert.True(t, sieve.IsPrime(1000003))
+		assert.False(t, sieve.IsPrime(1000001))
+	}
+	assert.Equal(t, int64(9), sieve.primality.hits)
+	assert.Equal(t, int64(2), sieve.primality.misses)
+
+	// values below 2 never reach the cache
+	assert.False(t, sieve.IsPrime(1))
+	assert.Equal(t, int64(2), sieve.primality.misses)
+}
+
+func TestPrimalityCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newPrimalityCache(2)
+	cache.put(2, true)
+	cache.put(4, false)
+
+	// touching 2 leaves 4 as the least recently used entry to evict
+	_, ok := cache.get(2)
+	assert.True(t, ok)
+	cache.put(5, true)
+
+	_, ok = cache.get(4)
+	assert.False(t, ok)
+	prime, ok := cache.get(2)
+	assert.True(t, ok)
+	assert.True(t, prime)
+	prime, ok = cache.get(5)
+	assert.True(t, ok)
+	assert.True(t, prime)
+	assert.Equal(t, 2, cache.order.Len())
+}
+
+func TestPrimalityCacheConcurrentUse(t *testing.T) {
+	sieve := NewPrimeNumberSieve(WithPrimalityCache(16))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := int64(0); n < 100; n++ {
+				assert.Equal(t, sieve.SmallestFactor(n) == n && n >= 2, sieve.IsPrime(n))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestPrimalityCacheDisabled(t *testing.T) {
+	assert.Nil(t, NewPrimeNumberSieve().primality)
+	assert.Nil(t, NewPrimeNumberSieve(WithPrimalityCache(0)).primality)
+}
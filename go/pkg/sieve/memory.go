@@ -0,0 +1,92 @@
+package sieve
+
+import (
+	"context"
+	"io"
+	"math"
+)
+
+// defaultMemoryLimit - the memory limit a new sieve starts with. Unlimited on 64 bit platforms, while on 32 bit platforms,
+// where the whole address space is 4GiB and int is 32 bits, the cache is capped at a conservative 256MiB
+var defaultMemoryLimit = func() int64 {
+	if math.MaxInt == math.MaxInt32 {
+		return 256 << 20
+	}
+	return 0
+}()
+
+// exceedsMemoryLimit - reports whether caching every prime up to bound is expected to use more than the memory limit
+func (s *PrimeNumberSieve) exceedsMemoryLimit(bound int64) bool {
+	return s.memoryLimit > 0 && estimatedCacheBytes(bound) > s.memoryLimit
+}
+
+// estimatedCacheBytes - roughly how many bytes caching every prime up to bound takes, using pi(x) ~ x/ln(x) primes
+// of 8 bytes each. Doubled since the result slice may have grown up to twice its length while collecting
+func estimatedCacheBytes(bound int64) int64 {
+	if bound < 2 {
+		return 0
+	}
+	return int64(float64(bound)/math.Log(float64(bound))) * 8 * 2
+}
+
+// streamNthPrime - finds the nth prime by counting primes one segment at a time without keeping them, so memory stays
+// proportional to sqrt(upperBounds) rather than the number of primes below it. Doubles upperBounds as needed,
+// carrying on from where the previous bound stopped instead of starting again. Stops with ctx.Err() once ctx is done
+func streamNthPrime(ctx context.Context, nthPrime, upperBounds int64, stats *SieveStats, trace io.Writer) (int64, error) {
+	var count int64 // primes found below low
+	low := int64(2) // the next number to be checked
+	for {
+		stats.Passes++
+		stats.UpperBound = upperBounds
+
+		baseLimit := isqrt(upperBounds)
+		primes := (&basicSieveOfEratosthenes{}).sieve(baseLimit)
+
+		// the base primes cover everything up to sqrt(upperBounds) themselves
+		for _, p := range primes {
+			if p < low {
+				continue
+			}
+			if count == nthPrime {
+				return p, nil
+			}
+			count++
+		}
+		if low <= baseLimit {
+			low = baseLimit + 1
+		}
+
+		var found int64
+		forEachSegment(primes, low, upperBounds, baseLimit, func(segmentLow int64, segment []bool) bool {
+			if ctx.Err() != nil {
+				return false
+			}
+			tracef(trace, "streaming segment [%d, %d]", segmentLow, segmentLow+int64(len(segment))-1)
+			for i, isPrime := range segment {
+				if !isPrime {
+					continue
+				}
+				if count == nthPrime {
+					found = segmentLow + int64(i)
+					return false
+				}
+				count++
+			}
+			return true
+		})
+		if found != 0 {
+			return found, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return 0, err
+		}
+		// every int64 has been checked, the nth prime doesn't fit in one
+		if upperBounds == math.MaxInt64 {
+			return 0, nil
+		}
+
+		tracef(trace, "n=%d not within %d primes up to %d, doubling upper bound to %d", nthPrime, count, upperBounds, doubleBound(upperBounds))
+		low = upperBounds + 1
+		upperBounds = doubleBound(upperBounds)
+	}
+}
@@ -0,0 +1,45 @@
+package sieve
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryLimitFallback(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping memory limit fallback in short mode")
+	}
+
+	// caching the primes up to NthPrime(1000000)'s bound needs megabytes, far more than this limit allows
+	sieve := NewPrimeNumberSieve(WithMemoryLimit(1 << 10))
+
+	prime, stats := sieve.NthPrimeWithStats(1000000)
+	assert.Equal(t, int64(15485867), prime)
+	assert.True(t, stats.Fallback)
+	assert.GreaterOrEqual(t, stats.UpperBound, prime)
+	assert.Empty(t, sieve.cache.primes)
+}
+
+func TestNoFallbackWithinMemoryLimit(t *testing.T) {
+	sieve := NewPrimeNumberSieve(WithMemoryLimit(1 << 30))
+
+	prime, stats := sieve.NthPrimeWithStats(1000)
+	assert.Equal(t, int64(7927), prime)
+	assert.False(t, stats.Fallback)
+	assert.NotEmpty(t, sieve.cache.primes)
+}
+
+func TestStreamNthPrimeMatchesSieve(t *testing.T) {
+	primes := (&segmentedSieve{}).sieve(100000)
+
+	for _, n := range []int64{0, 1, 2, 5, 24, 25, 26, 99, 1000, int64(len(primes)) - 1} {
+		// starting from a tiny bound forces the stream to double and carry on several times
+		stats := SieveStats{}
+		prime, err := streamNthPrime(context.Background(), n, 4, &stats, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, primes[n], prime, "n=%d", n)
+		assert.GreaterOrEqual(t, stats.UpperBound, primes[n])
+	}
+}
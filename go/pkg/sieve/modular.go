@@ -0,0 +1,49 @@
+package sieve
+
+import "math/bits"
+
+// mulmod - returns (a * b) mod m without overflowing, for 0 <= a, b < m. The full 128 bit product is reduced directly
+func mulmod(a, b, m int64) int64 {
+	hi, lo := bits.Mul64(uint64(a), uint64(b))
+	return int64(bits.Rem64(hi, lo, uint64(m)))
+}
+
+// gcd - the greatest common divisor of a and b, for a, b >= 0
+func gcd(a, b int64) int64 {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
+
+// powmod - returns (base ^ exp) mod m without overflowing, for 0 <= base < m and exp >= 0, by square and multiply
+func powmod(base, exp, m int64) int64 {
+	res := int64(1) % m
+	for ; exp > 0; exp >>= 1 {
+		if exp&1 == 1 {
+			res = mulmod(res, base, m)
+		}
+		base = mulmod(base, base, m)
+	}
+	return res
+}
+
+// primitiveRoot - returns the smallest generator of the multiplicative group mod the prime p, given the distinct prime
+// factors of p - 1. g generates the group when g^((p-1)/q) != 1 for every prime q dividing p - 1
+func primitiveRoot(p int64, factors []int64) int64 {
+	if p == 2 {
+		return 1
+	}
+	for g := int64(2); ; g++ {
+		generator := true
+		for _, q := range factors {
+			if powmod(g, (p-1)/q, p) == 1 {
+				generator = false
+				break
+			}
+		}
+		if generator {
+			return g
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package sieve
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMulmod(t *testing.T) {
+	assert.Equal(t, int64(10), mulmod(30, 7, 100))
+	assert.Equal(t, int64(0), mulmod(0, 99, 100))
+
+	// products far beyond int64 are reduced exactly
+	m := int64(math.MaxInt64)
+	a, b := m-1, m-2
+	want := new(big.Int).Mod(new(big.Int).Mul(big.NewInt(a), big.NewInt(b)), big.NewInt(m))
+	assert.Equal(t, want.Int64(), mulmod(a, b, m))
+}
+
+func TestGcd(t *testing.T) {
+	assert.Equal(t, int64(6), gcd(12, 18))
+	assert.Equal(t, int64(1), gcd(17, 5))
+	assert.Equal(t, int64(7), gcd(0, 7))
+	assert.Equal(t, int64(7), gcd(7, 0))
+}
+
+func TestPowmod(t *testing.T) {
+	assert.Equal(t, int64(24), powmod(2, 10, 1000))
+	assert.Equal(t, int64(1), powmod(7, 0, 13))
+	assert.Equal(t, int64(0), powmod(5, 3, 1))
+
+	// Fermat's little theorem holds even for moduli near math.MaxInt64
+	p := int64(9223372036854775783)
+	assert.Equal(t, int64(1), powmod(123456789, p-1, p))
+}
+
+func TestPrimitiveRoot(t *testing.T) {
+	assert.Equal(t, int64(1), primitiveRoot(2, []int64{}))
+	assert.Equal(t, int64(2), primitiveRoot(11, []int64{2, 5}))
+	assert.Equal(t, int64(3), primitiveRoot(7, []int64{2, 3}))
+	assert.Equal(t, int64(5), primitiveRoot(23, []int64{2, 11}))
+
+	// a generator reaches every nonzero residue
+	g, p := primitiveRoot(101, []int64{2, 5}), int64(101)
+	seen := make(map[int64]bool)
+	for v, i := int64(1), 0; i < 100; i++ {
+		v = mulmod(v, g, p)
+		seen[v] = true
+	}
+	assert.Len(t, seen, 100)
+}
@@ -0,0 +1,69 @@
+package sieve
+
+import "math"
+
+// navigationWindow - how many numbers NextPrime and PrevPrime search at a time, comfortably more than the largest
+// gap between consecutive primes in most of the int64 range so one window is almost always enough
+const navigationWindow = 1 << 10
+
+// navigationSieveLimit - the largest window NextPrime and PrevPrime sieve. Above it sieving a window would first need
+// the primes up to its square root, so each candidate is tested by Miller-Rabin instead
+const navigationSieveLimit = 1 << 40
+
+// NextPrime - returns the smallest prime strictly greater than n, so 2 for any n below 2. Only a small window above n
+// is searched rather than sieving from 2. Returns 0 if there is no larger prime in the int64 range
+func (s *PrimeNumberSieve) NextPrime(n int64) int64 {
+	if n < 2 {
+		return 2
+	}
+
+	// lo wraps negative once it passes math.MaxInt64
+	for lo := n + 1; lo > 0; lo += navigationWindow {
+		hi := int64(math.MaxInt64)
+		if lo <= math.MaxInt64-navigationWindow {
+			hi = lo + navigationWindow - 1
+		}
+		if hi <= navigationSieveLimit {
+			if primes := s.PrimesInRange(lo, hi); len(primes) > 0 {
+				return primes[0]
+			}
+			continue
+		}
+		for v := lo; ; v++ {
+			if millerRabin(v) {
+				return v
+			}
+			if v == hi {
+				break
+			}
+		}
+	}
+	return 0
+}
+
+// PrevPrime - returns the largest prime strictly less than n, with ok false if there isn't one because n <= 2.
+// Like NextPrime only a small window below n is searched
+func (s *PrimeNumberSieve) PrevPrime(n int64) (prime int64, ok bool) {
+	if n <= 2 {
+		return 0, false
+	}
+
+	for hi := n - 1; hi >= 2; hi -= navigationWindow {
+		lo := int64(2)
+		if hi-lo >= navigationWindow {
+			lo = hi - navigationWindow + 1
+		}
+		if hi <= navigationSieveLimit {
+			if primes := s.PrimesInRange(lo, hi); len(primes) > 0 {
+				return primes[len(primes)-1], true
+			}
+			continue
+		}
+		for v := hi; v >= lo; v-- {
+			if millerRabin(v) {
+				return v, true
+			}
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,61 @@
+package sieve
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNextPrime(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	for n, want := range map[int64]int64{
+		math.MinInt64: 2, -5: 2, 0: 2, 1: 2, 2: 3, 3: 5, 13: 17, 14: 17, 16: 17, 540: 541, 541: 547,
+		1000000: 1000003, 1000000000000: 1000000000039, navigationSieveLimit: 1099511627791,
+		9223372036854775782: 9223372036854775783,
+	} {
+		assert.Equal(t, want, sieve.NextPrime(n), "n=%d", n)
+	}
+
+	// there's no prime above the largest int64 prime
+	assert.Equal(t, int64(0), sieve.NextPrime(9223372036854775783))
+	assert.Equal(t, int64(0), sieve.NextPrime(math.MaxInt64))
+}
+
+func TestPrevPrime(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	for n, want := range map[int64]int64{
+		3: 2, 4: 3, 13: 11, 17: 13, 18: 17, 548: 547, 1000000: 999983, 1000003: 999983,
+		1000000000000: 999999999989, math.MaxInt64: 9223372036854775783,
+	} {
+		prime, ok := sieve.PrevPrime(n)
+		assert.True(t, ok, "n=%d", n)
+		assert.Equal(t, want, prime, "n=%d", n)
+	}
+
+	for _, n := range []int64{2, 1, 0, -7, math.MinInt64} {
+		prime, ok := sieve.PrevPrime(n)
+		assert.False(t, ok, "n=%d", n)
+		assert.Equal(t, int64(0), prime, "n=%d", n)
+	}
+}
+
+func TestNextPrevPrimeMatchSieve(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	primes := sieve.PrimesUpTo(20000)
+
+	// walk the primes forwards and back across several windows, including their gaps
+	for i := 0; i+1 < len(primes); i++ {
+		for n := primes[i]; n < primes[i+1]; n++ {
+			if !assert.Equal(t, primes[i+1], sieve.NextPrime(n), "n=%d", n) {
+				return
+			}
+			prev, _ := sieve.PrevPrime(n + 1)
+			if !assert.Equal(t, primes[i], prev, "n=%d", n+1) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package sieve
+
+import (
+	"fmt"
+	"io"
+)
+
+// Option - configures optional behaviour of a PrimeNumberSieve, passed to NewPrimeNumberSieve
+type Option func(*PrimeNumberSieve)
+
+// WithTrace - writes a step-by-step trace of the bound estimation, each sieve segment's range and the
+// doubling decisions to w. Tracing is off by default and is only intended for debugging
+func WithTrace(w io.Writer) Option {
+	return func(s *PrimeNumberSieve) {
+		s.trace = w
+	}
+}
+
+// tracef - writes a single trace line to w, does nothing when tracing is disabled (w is nil)
+func tracef(w io.Writer, format string, args ...interface{}) {
+	if w == nil {
+		return
+	}
+	fmt.Fprintf(w, format+"\n", args...)
+}
+
+// WithMemoryLimit - caps the memory, in bytes, the sieve may use to cache primes. Queries that would need more than
+// this fall back to streaming primes segment by segment, which is slower and caches nothing but needs very little memory.
+// A limit of 0 means unlimited, the default on 64 bit platforms. 32 bit platforms default to a 256MiB limit
+func WithMemoryLimit(bytes int64) Option {
+	return func(s *PrimeNumberSieve) {
+		s.memoryLimit = bytes
+	}
+}
+
+// WithVerifyOnLoad - when enabled, LoadFrom checks the decoded primes are exactly the primes up to the table's bound,
+// strictly increasing, each prime and none missing, before trusting them as the cache, so a corrupted file can't cause
+// wrong answers. Off by default, as finding a missing prime means a Miller-Rabin test of every odd number in the table
+func WithVerifyOnLoad(verify bool) Option {
+	return func(s *PrimeNumberSieve) {
+		s.verifyOnLoad = verify
+	}
+}
+
+// WithAdaptiveSegments - when enabled, the segmented sieve grows each segment logarithmically with its distance from
+// zero rather than using a fixed size of sqrt(n), keeping the number of primes found per segment roughly constant.
+// The primes found are identical either way. Off by default
+func WithAdaptiveSegments(adaptive bool) Option {
+	return func(s *PrimeNumberSieve) {
+		s.adaptiveSegments = adaptive
+	}
+}
+
+// WithTracer - starts a span on t around every NthPrime query, recording the index, the upper bound sieved to and the
+// algorithm used. Tracing is a no-op by default
+func WithTracer(t Tracer) Option {
+	return func(s *PrimeNumberSieve) {
+		if t == nil {
+			t = noopTracer{}
+		}
+		s.tracer = t
+	}
+}
+
+// WithBatchConcurrency - bounds how many lookups NthPrimeMany runs at once, so a huge batch can't spawn unbounded work.
+// Values below 1 use the default of runtime.GOMAXPROCS(0)
+func WithBatchConcurrency(k int) Option {
+	return func(s *PrimeNumberSieve) {
+		s.batchConcurrency = k
+	}
+}
+
+// WithPrimalityCache - memoizes up to size of the most recently used IsPrime results, for workloads that test the same
+// numbers repeatedly. The least recently used result is evicted once the cache is full. A size of 0 (the default)
+// disables the cache
+func WithPrimalityCache(size int) Option {
+	return func(s *PrimeNumberSieve) {
+		s.primality = nil
+		if size > 0 {
+			s.primality = newPrimalityCache(size)
+		}
+	}
+}
+
+// WithFanOut - buffers up to buffer primes per PrimesFanOut consumer, applying policy once a consumer's buffer is full.
+// Defaults to a buffer of 64 with the FanOutBlock policy, a buffer below 1 uses the default size
+func WithFanOut(buffer int, policy FanOutPolicy) Option {
+	return func(s *PrimeNumberSieve) {
+		s.fanOutBuffer, s.fanOutPolicy = buffer, policy
+	}
+}
+
+// WithWorkers - sieves segments across n goroutines at once, capped at runtime.GOMAXPROCS(0). Values below 1 use the
+// default of one worker per CPU, runtime.NumCPU(). A single worker sieves every segment serially
+func WithWorkers(n int) Option {
+	return func(s *PrimeNumberSieve) {
+		s.workers = n
+	}
+}
+
+// WithCrossCheck - has IsPrimeChecked answer every value the sieve covers both from the sieve and by Miller-Rabin,
+// returning an error wrapping ErrCrossCheckFailed if they disagree. A canary for overflow or logic bugs on unusual
+// platforms, off by default as it doubles the work of each check
+func WithCrossCheck(enabled bool) Option {
+	return func(s *PrimeNumberSieve) {
+		s.crossCheck = enabled
+	}
+}
+
+// WithDebugVerify - a development aid that checks every result of the segmented sieve against the basic sieve of
+// eratosthenes for the same bound, panicking with the first difference if they disagree so a segment boundary bug
+// can't go unnoticed. Off by default, and best left off in production as it more than doubles the work and memory of
+// every sieve. Other algorithms are unaffected
+func WithDebugVerify(enabled bool) Option {
+	return func(s *PrimeNumberSieve) {
+		s.debugVerify = enabled
+	}
+}
+
+// WithSharedCache - caches primes in c rather than a cache of the sieve's own, sharing them with every other sieve
+// using c. Each sieve holds a reference to c until it's Closed. A nil c leaves the sieve with its own cache
+func WithSharedCache(c *SharedCache) Option {
+	return func(s *PrimeNumberSieve) {
+		if c == nil {
+			return
+		}
+		c.cache.acquire()
+		s.cache.release()
+		s.cache = c.cache
+	}
+}
+
+// WithAlgorithm - selects the internal sieve used to find primes, the segmented sieve by default.
+// Every algorithm finds the same primes, unknown algorithms use the default rather than failing
+func WithAlgorithm(a Algorithm) Option {
+	return func(s *PrimeNumberSieve) {
+		factory, ok := sieveFactories[a]
+		if !ok {
+			factory = sieveFactories[AlgorithmSegmented]
+		}
+		s.newSieveFunc = factory
+	}
+}
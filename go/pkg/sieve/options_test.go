@@ -0,0 +1,56 @@
+package sieve
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTrace(t *testing.T) {
+	var trace bytes.Buffer
+	sieve := NewPrimeNumberSieve(WithTrace(&trace))
+
+	assert.Equal(t, int64(31), sieve.NthPrime(10))
+
+	out := trace.String()
+	assert.Contains(t, out, "estimated upper bound 36 for n=10")
+	assert.Contains(t, out, "sieving segment [7, 12]")
+	assert.Contains(t, out, "found n=10 within 11 primes up to 36")
+}
+
+func TestTraceDoubling(t *testing.T) {
+	var trace bytes.Buffer
+	sieve := NewPrimeNumberSieve(WithTrace(&trace))
+
+	// the estimate never undershoots, so start from a bound of 20 which only holds 8 primes, forcing a single
+	// doubling to 40
+	sieve.cache.mu.Lock()
+	prime, stats, _ := sieve.nthPrimeFromLocked(context.Background(), 10, 20, SieveStats{})
+	sieve.cache.mu.Unlock()
+	assert.Equal(t, int64(31), prime)
+	assert.Equal(t, 2, stats.Passes)
+
+	out := trace.String()
+	assert.Contains(t, out, "n=10 not within 8 primes up to 20, doubling upper bound to 40")
+	assert.Contains(t, out, "found n=10 within 12 primes up to 40")
+}
+
+func TestTraceOffByDefault(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	assert.Nil(t, sieve.trace)
+	assert.Equal(t, int64(31), sieve.NthPrime(10))
+}
+
+func TestWithAlgorithm(t *testing.T) {
+	assert.Equal(t, "segmented", NewPrimeNumberSieve().newSieve().name())
+	assert.Equal(t, "basic", NewPrimeNumberSieve(WithAlgorithm(AlgorithmBasic)).newSieve().name())
+	assert.Equal(t, "atkin", NewPrimeNumberSieve(WithAlgorithm(AlgorithmAtkin)).newSieve().name())
+	assert.Equal(t, "wheel", NewPrimeNumberSieve(WithAlgorithm(AlgorithmWheel)).newSieve().name())
+
+	// unknown algorithms fall back to the default
+	sieve := NewPrimeNumberSieve(WithAlgorithm(Algorithm(42)))
+	assert.Equal(t, "segmented", sieve.newSieve().name())
+	assert.Equal(t, int64(541), sieve.NthPrime(99))
+}
@@ -0,0 +1,31 @@
+package sieve
+
+import "fmt"
+
+// PackPrimes - packs the first n primes into a buffer of fixed width, big-endian, unsigned integers of bytesPerPrime
+// bytes each, for binary wire formats. Returns an error wrapping ErrNegativeIndex if n is negative, or ErrOverflow if a
+// prime doesn't fit in bytesPerPrime bytes
+func (s *PrimeNumberSieve) PackPrimes(n int64, bytesPerPrime int) ([]byte, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("%w: cannot pack the first %d primes", ErrNegativeIndex, n)
+	}
+	if n > 0 && bytesPerPrime < 1 {
+		return nil, fmt.Errorf("%w: primes don't fit in %d bytes", ErrOverflow, bytesPerPrime)
+	}
+
+	// the primes are increasing, so only the largest needs checking against the width
+	primes := s.firstPrimes(n)
+	if n > 0 && bytesPerPrime < 8 && primes[n-1] >= int64(1)<<(8*bytesPerPrime) {
+		return nil, fmt.Errorf("%w: prime %d doesn't fit in %d bytes", ErrOverflow, primes[n-1], bytesPerPrime)
+	}
+
+	buf := make([]byte, int(n)*bytesPerPrime)
+	for i, p := range primes {
+		// write the least significant byte last, any width beyond 8 bytes is left zero padded
+		for b := (i+1)*bytesPerPrime - 1; p > 0; b-- {
+			buf[b] = byte(p)
+			p >>= 8
+		}
+	}
+	return buf, nil
+}
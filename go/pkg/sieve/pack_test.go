@@ -0,0 +1,52 @@
+package sieve
+
+import (
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackPrimes(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	buf, err := sieve.PackPrimes(5, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 2, 0, 3, 0, 5, 0, 7, 0, 11}, buf)
+
+	unpacked := make([]int64, 0)
+	for i := 0; i < len(buf); i += 2 {
+		unpacked = append(unpacked, int64(binary.BigEndian.Uint16(buf[i:])))
+	}
+	assert.Equal(t, sieve.PrimesUpTo(11), unpacked)
+
+	// primes wider than a byte, and widths beyond an int64 are zero padded
+	buf, err = sieve.PackPrimes(100, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(541), binary.BigEndian.Uint32(buf[99*4:]))
+
+	buf, err = sieve.PackPrimes(2, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 0, 0, 3}, buf)
+
+	buf, err = sieve.PackPrimes(0, 2)
+	assert.NoError(t, err)
+	assert.Empty(t, buf)
+}
+
+func TestPackPrimesErrors(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// 54 primes fit in a byte, the 55th is 257
+	_, err := sieve.PackPrimes(54, 1)
+	assert.NoError(t, err)
+	_, err = sieve.PackPrimes(55, 1)
+	assert.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = sieve.PackPrimes(5, 0)
+	assert.True(t, errors.Is(err, ErrOverflow))
+
+	_, err = sieve.PackPrimes(-1, 2)
+	assert.True(t, errors.Is(err, ErrNegativeIndex))
+}
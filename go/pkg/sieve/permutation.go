@@ -0,0 +1,39 @@
+package sieve
+
+// PrimePermutation - returns a bijection on [0, n), shuffling indices without storing a permutation. Indices are
+// shifted to [1, n] and multiplied by a generator g of the integers mod p, the smallest prime > n, walking the cycle
+// x, xg, xg^2, ... mod p until it lands back within [1, n]. Multiplying by g permutes [1, p-1], so restricting it to
+// [1, n] this way is still a permutation, and since p is the next prime after n only a few steps are ever needed.
+// Values of n below 2 return the identity, while an n with no prime above it in the int64 range returns nil
+func (s *PrimeNumberSieve) PrimePermutation(n int64) func(int64) int64 {
+	if n < 2 {
+		return func(x int64) int64 { return x }
+	}
+
+	p := s.NextPrime(n)
+	if p == 0 {
+		return nil
+	}
+	g := primitiveRoot(p, s.distinctPrimeFactors(p-1))
+
+	return func(x int64) int64 {
+		v := x + 1
+		for {
+			v = mulmod(v, g, p)
+			if v <= n {
+				return v - 1
+			}
+		}
+	}
+}
+
+// distinctPrimeFactors - returns the distinct prime factors of n in ascending order
+func (s *PrimeNumberSieve) distinctPrimeFactors(n int64) []int64 {
+	factors := make([]int64, 0)
+	for _, p := range s.Factorize(n) {
+		if len(factors) == 0 || factors[len(factors)-1] != p {
+			factors = append(factors, p)
+		}
+	}
+	return factors
+}
@@ -0,0 +1,37 @@
+package sieve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrimePermutation(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	for _, n := range []int64{2, 3, 10, 100, 1000, 65536, 100003} {
+		perm := sieve.PrimePermutation(n)
+
+		seen := make(map[int64]bool, n)
+		for x := int64(0); x < n; x++ {
+			y := perm(x)
+			assert.True(t, y >= 0 && y < n, "n=%d: %d maps to %d", n, x, y)
+			assert.False(t, seen[y], "n=%d: %d maps to %d twice", n, x, y)
+			seen[y] = true
+		}
+		assert.Len(t, seen, int(n))
+	}
+
+	// the shuffle actually moves indices around
+	perm := sieve.PrimePermutation(1000)
+	moved := 0
+	for x := int64(0); x < 1000; x++ {
+		if perm(x) != x {
+			moved++
+		}
+	}
+	assert.Greater(t, moved, 900)
+
+	assert.Equal(t, int64(0), sieve.PrimePermutation(1)(0))
+	assert.Equal(t, int64(5), sieve.PrimePermutation(0)(5))
+}
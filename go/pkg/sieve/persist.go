@@ -0,0 +1,130 @@
+package sieve
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+// tableMagic - identifies a serialized prime table, followed by its format version
+const (
+	tableMagic   = "PRMS"
+	tableVersion = 1
+)
+
+// SaveTo - writes the cached primes to w in a compact binary format that LoadFrom can restore.
+// The table is written as:
+//
+//	"PRMS", a version byte
+//	uvarint bound, every prime up to bound is in the table
+//	uvarint count of primes
+//	count uvarints, each the gap from the previous prime (the first is the gap from 0)
+func (s *PrimeNumberSieve) SaveTo(w io.Writer) error {
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	buf := append([]byte(tableMagic), tableVersion)
+	buf = binary.AppendUvarint(buf, uint64(s.cache.bound))
+	buf = binary.AppendUvarint(buf, uint64(len(s.cache.primes)))
+	if _, err := bw.Write(buf); err != nil {
+		return err
+	}
+
+	prev := int64(0)
+	for _, p := range s.cache.primes {
+		buf = binary.AppendUvarint(buf[:0], uint64(p-prev))
+		if _, err := bw.Write(buf); err != nil {
+			return err
+		}
+		prev = p
+	}
+
+	return bw.Flush()
+}
+
+// LoadFrom - restores a prime table written by SaveTo, using it as the cache if it covers more than the current cache.
+// Malformed or truncated input returns an error wrapping ErrCorruptTable and leaves the cache untouched.
+// With WithVerifyOnLoad the decoded primes are also checked to be exactly the primes up to the table's bound
+func (s *PrimeNumberSieve) LoadFrom(r io.Reader) error {
+	primes, bound, err := readTable(bufio.NewReader(r))
+	if err != nil {
+		return err
+	}
+
+	if s.verifyOnLoad {
+		if err := verifyTable(primes, bound); err != nil {
+			return err
+		}
+	}
+
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	if bound > s.cache.bound {
+		s.cache.primes, s.cache.bound = primes, bound
+	}
+	return nil
+}
+
+// readTable - decodes a table written by SaveTo, returning its primes and the bound they cover
+func readTable(r *bufio.Reader) ([]int64, int64, error) {
+	header := make([]byte, len(tableMagic)+1)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, 0, corruptTable("reading header", err)
+	}
+	if string(header[:len(tableMagic)]) != tableMagic {
+		return nil, 0, fmt.Errorf("%w: not a prime table", ErrCorruptTable)
+	}
+	if header[len(tableMagic)] != tableVersion {
+		return nil, 0, fmt.Errorf("%w: unsupported version %d", ErrCorruptTable, header[len(tableMagic)])
+	}
+
+	bound, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, 0, corruptTable("reading bound", err)
+	}
+	count, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, 0, corruptTable("reading count", err)
+	}
+	if bound > math.MaxInt64 || count > bound {
+		return nil, 0, fmt.Errorf("%w: %d primes up to %d is impossible", ErrCorruptTable, count, bound)
+	}
+
+	// don't trust count for the initial allocation, a corrupt header could ask for anything
+	primes := make([]int64, 0, minInt64(int64(count), 1<<16))
+	prev := uint64(0)
+	for i := uint64(0); i < count; i++ {
+		gap, err := binary.ReadUvarint(r)
+		if err != nil {
+			return nil, 0, corruptTable("reading primes", err)
+		}
+		if gap > bound-prev {
+			return nil, 0, fmt.Errorf("%w: prime %d exceeds the table's bound %d", ErrCorruptTable, i, bound)
+		}
+		prev += gap
+		primes = append(primes, int64(prev))
+	}
+
+	return primes, int64(bound), nil
+}
+
+// corruptTable - wraps an error from reading a table, treating a table that ends early as corrupt
+func corruptTable(step string, err error) error {
+	if errors.Is(err, io.EOF) {
+		err = io.ErrUnexpectedEOF
+	}
+	return fmt.Errorf("%w: %s: %v", ErrCorruptTable, step, err)
+}
+
+// minInt64 - returns the smaller of a and b
+func minInt64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,50 @@
+package sieve
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	saved := NewPrimeNumberSieve()
+	saved.Prewarm(1000)
+
+	var buf bytes.Buffer
+	assert.NoError(t, saved.SaveTo(&buf))
+	size := buf.Len()
+
+	loaded := NewPrimeNumberSieve(WithVerifyOnLoad(true))
+	assert.NoError(t, loaded.LoadFrom(&buf))
+	assert.Equal(t, saved.cache.primes, loaded.cache.primes)
+	assert.Equal(t, saved.cache.bound, loaded.cache.bound)
+
+	// queries within the loaded table are served from it without sieving
+	prime, stats := loaded.NthPrimeWithStats(999)
+	assert.Equal(t, int64(7919), prime)
+	assert.Equal(t, 0, stats.Passes)
+
+	// and the gap encoding keeps the table to a couple of bytes per prime
+	assert.Less(t, size, 2*len(saved.cache.primes)+16)
+}
+
+func TestLoadTruncatedTable(t *testing.T) {
+	saved := NewPrimeNumberSieve()
+	saved.Prewarm(1000)
+
+	var buf bytes.Buffer
+	assert.NoError(t, saved.SaveTo(&buf))
+	table := buf.Bytes()
+
+	// every prefix short of the whole table is an error rather than a panic, leaving the cache empty
+	for n := 0; n < len(table); n++ {
+		sieve := NewPrimeNumberSieve()
+		err := sieve.LoadFrom(bytes.NewReader(table[:n]))
+		if !assert.True(t, errors.Is(err, ErrCorruptTable), "prefix of %d bytes", n) {
+			break
+		}
+		assert.Empty(t, sieve.cache.primes)
+	}
+}
@@ -0,0 +1,67 @@
+package sieve
+
+import "math"
+
+// SievePlan - describes the work NthPrime would do to find a prime, without doing any of it
+type SievePlan struct {
+	// UpperBound - the bound the first pass would sieve up to
+	UpperBound int64
+	// SegmentSize - how many numbers each segment covers, sqrt(UpperBound) for the segmented sieve. The other
+	// algorithms don't segment, so sieve the whole bound as one
+	SegmentSize int64
+	// Segments - how many segments cover (SegmentSize, UpperBound] after the base primes up to SegmentSize are found,
+	// or 1 for the algorithms that don't segment
+	Segments int64
+	// EstimatedBytes - roughly how much memory caching the primes up to UpperBound takes, see estimatedCacheBytes
+	EstimatedBytes int64
+	// Cached - true if the prime is already cached and there's nothing to sieve
+	Cached bool
+	// Fallback - true if caching the primes would exceed the memory limit, so they'd be streamed instead
+	Fallback bool
+}
+
+// PlanSieve - returns the plan for finding the nth prime, for inspecting the cost of a query before running it such as in
+// a dry run. Only the first pass is planned, which the upper bound estimate makes the only one needed.
+// A negative index, or one too large for its prime to fit in an int64, has nothing to plan and returns an empty plan
+func (s *PrimeNumberSieve) PlanSieve(nthPrime int64) SievePlan {
+	if nthPrime < 0 || nthPrime > maxPrimeIndex {
+		return SievePlan{}
+	}
+	if s.CacheCovers(nthPrime) {
+		return SievePlan{Cached: true}
+	}
+
+	upperBounds := EstimateUpperBound(nthPrime)
+	plan := SievePlan{
+		UpperBound:     upperBounds,
+		SegmentSize:    upperBounds,
+		Segments:       1,
+		EstimatedBytes: estimatedCacheBytes(upperBounds),
+		Fallback:       s.exceedsMemoryLimit(upperBounds),
+	}
+
+	// bounds below 4 are sieved without segmenting, as in segmentedSieve.sieveContext
+	if segmented, ok := s.newSieve().(*segmentedSieve); ok && upperBounds >= 4 {
+		plan.SegmentSize = int64(math.Sqrt(float64(upperBounds)))
+		plan.Segments = countSegments(plan.SegmentSize, upperBounds, segmented.adaptive)
+	}
+	return plan
+}
+
+// countSegments - how many segments segmentBoundsFrom splits (segmentSize, n] into, without allocating them
+func countSegments(segmentSize, n int64, adaptive bool) int64 {
+	if !adaptive {
+		return (n - 1) / segmentSize
+	}
+
+	var count int64
+	for low := segmentSize + 1; low <= n; count++ {
+		// n - low rather than low + size so the last segment can't overflow
+		size := adaptiveSegmentSize(segmentSize, low)
+		if n-low < size {
+			return count + 1
+		}
+		low += size
+	}
+	return count
+}
@@ -0,0 +1,50 @@
+package sieve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanSieve(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	for _, n := range []int64{2, 10, 99, 1000, 123456, 1000000, 100000000} {
+		plan := sieve.PlanSieve(n)
+		assert.Equal(t, EstimateUpperBound(n), plan.UpperBound, "n=%d", n)
+
+		// the base primes and the segments together cover the bound exactly once
+		assert.Less(t, plan.Segments*plan.SegmentSize, plan.UpperBound, "n=%d", n)
+		assert.GreaterOrEqual(t, (plan.Segments+1)*plan.SegmentSize, plan.UpperBound, "n=%d", n)
+		assert.Equal(t, int64(len(segmentBoundsFrom(plan.SegmentSize+1, plan.SegmentSize, plan.UpperBound, false))), plan.Segments, "n=%d", n)
+		assert.Positive(t, plan.EstimatedBytes, "n=%d", n)
+		assert.False(t, plan.Cached || plan.Fallback, "n=%d", n)
+	}
+
+	// planning sieves nothing
+	assert.Zero(t, sieve.cache.bound)
+
+	// and once sieved there's nothing left to plan
+	sieve.NthPrime(1000)
+	assert.Equal(t, SievePlan{Cached: true}, sieve.PlanSieve(1000))
+
+	assert.Equal(t, SievePlan{}, sieve.PlanSieve(-1))
+	assert.Equal(t, SievePlan{}, sieve.PlanSieve(maxPrimeIndex+1))
+}
+
+func TestPlanSieveOptions(t *testing.T) {
+	// adaptive segments grow, so fewer are needed
+	for _, n := range []int64{1000, 1000000, 100000000} {
+		plan := NewPrimeNumberSieve(WithAdaptiveSegments(true)).PlanSieve(n)
+		assert.Equal(t, int64(len(segmentBoundsFrom(plan.SegmentSize+1, plan.SegmentSize, plan.UpperBound, true))), plan.Segments, "n=%d", n)
+		assert.Less(t, plan.Segments, NewPrimeNumberSieve().PlanSieve(n).Segments, "n=%d", n)
+	}
+
+	// the unsegmented algorithms sieve the bound in one go
+	plan := NewPrimeNumberSieve(WithAlgorithm(AlgorithmAtkin)).PlanSieve(1000)
+	assert.Equal(t, int64(1), plan.Segments)
+	assert.Equal(t, plan.UpperBound, plan.SegmentSize)
+
+	// the memory limit is applied as NthPrime would
+	assert.True(t, NewPrimeNumberSieve(WithMemoryLimit(1<<10)).PlanSieve(1000000).Fallback)
+}
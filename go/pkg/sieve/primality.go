@@ -0,0 +1,186 @@
+package sieve
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// IsPrime - reports whether n is a prime number, any n below 2 is not prime. Values the small primes table or the
+// cache already cover are looked up, anything larger is tested by deterministic Miller-Rabin rather than sieving up to
+// n or trial dividing up to its square root. Results are memoized when the sieve has a WithPrimalityCache
+func (s *PrimeNumberSieve) IsPrime(n int64) bool {
+	if prime, ok := s.sievedPrimality(n); ok {
+		if n >= 2 {
+			s.cache.record(true)
+		}
+		return prime
+	}
+	if s.primality == nil {
+		s.cache.record(false)
+		return millerRabin(n)
+	}
+
+	if prime, ok := s.primality.get(n); ok {
+		s.cache.record(true)
+		return prime
+	}
+	s.cache.record(false)
+	prime := millerRabin(n)
+	s.primality.put(n, prime)
+	return prime
+}
+
+// IsPrimeChecked - same as IsPrime, but with WithCrossCheck enabled any n already covered by the sieve is looked up
+// in the sieved primes and also tested by Miller-Rabin, returning an error wrapping ErrCrossCheckFailed if the two
+// disagree. Values the sieve doesn't cover, or any value without cross checking, are never an error
+func (s *PrimeNumberSieve) IsPrimeChecked(n int64) (bool, error) {
+	if !s.crossCheck {
+		return s.IsPrime(n), nil
+	}
+
+	sieved, ok := s.sievedPrimality(n)
+	if !ok {
+		return s.IsPrime(n), nil
+	}
+	s.cache.record(true)
+	if probable := s.probablePrime(n); probable != sieved {
+		return false, fmt.Errorf("%w: the sieve reports %d prime as %t but Miller-Rabin as %t", ErrCrossCheckFailed, n, sieved, probable)
+	}
+	return sieved, nil
+}
+
+// sievedPrimality - looks n up in the small primes or the cached primes, ok is false if neither covers it
+func (s *PrimeNumberSieve) sievedPrimality(n int64) (prime, ok bool) {
+	if n < 2 {
+		return false, true
+	}
+	if n <= smallPrimesBound {
+		i := sort.Search(len(smallPrimes), func(i int) bool { return smallPrimes[i] >= n })
+		return smallPrimes[i] == n, true
+	}
+
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	if n > s.cache.bound {
+		return false, false
+	}
+	primes := s.cache.primes
+	i := sort.Search(len(primes), func(i int) bool { return primes[i] >= n })
+	return i < len(primes) && primes[i] == n, true
+}
+
+// millerRabinBases - testing against every prime up to 37 makes Miller-Rabin deterministic for every n below 2^64
+var millerRabinBases = []int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}
+
+// millerRabin - reports whether n is prime by the deterministic Miller-Rabin test, with no sieving at all.
+// Writes n - 1 as d * 2^r and checks each base a either has a^d = 1 or reaches -1 within r squarings
+func millerRabin(n int64) bool {
+	if n < 2 {
+		return false
+	}
+	for _, p := range millerRabinBases {
+		if n%p == 0 {
+			return n == p
+		}
+	}
+
+	d, r := n-1, 0
+	for d%2 == 0 {
+		d /= 2
+		r++
+	}
+
+	for _, a := range millerRabinBases {
+		x := powmod(a, d, n)
+		if x == 1 || x == n-1 {
+			continue
+		}
+		witness := true
+		for i := 1; i < r && witness; i++ {
+			x = mulmod(x, x, n)
+			witness = x != n-1
+		}
+		if witness {
+			return false
+		}
+	}
+	return true
+}
+
+// isqrt - returns the largest integer r such that r*r <= n, correcting for any float rounding in math.Sqrt
+func isqrt(n int64) int64 {
+	if n < 1 {
+		return 0
+	}
+
+	r := int64(math.Sqrt(float64(n)))
+	// compare using division so large values of n can't overflow
+	for r > n/r {
+		r--
+	}
+	for r+1 <= n/(r+1) {
+		r++
+	}
+	return r
+}
+
+// VerifyPrimeList - checks a precomputed list of primes, such as a loaded table, is every prime between its smallest
+// and largest entry in strictly ascending order. Returns the offending values: entries that aren't prime or are out of
+// order (including duplicates), followed by any primes missing from the range. ok is true when there are none.
+// Each entry is tested with IsPrime, so a list of huge primes is cheap to check, but the range is only searched for
+// missing primes when it spans at most verifyListSpan numbers. Wider lists are checked entry by entry only
+func (s *PrimeNumberSieve) VerifyPrimeList(primes []int64) (bad []int64, ok bool) {
+	bad = make([]int64, 0)
+	if len(primes) == 0 {
+		return bad, true
+	}
+
+	listed := make([]int64, 0, len(primes))
+	lo, hi := primes[0], primes[0]
+	for _, p := range primes {
+		lo, hi = minInt64(lo, p), max(hi, p)
+		if !s.IsPrime(p) || len(listed) > 0 && p <= listed[len(listed)-1] {
+			bad = append(bad, p)
+			continue
+		}
+		listed = append(listed, p)
+	}
+
+	// sieve the range for any prime the list skipped, with both lists ascending a single merge finds them
+	if lo = max(lo, 2); hi >= lo && hi-lo < verifyListSpan {
+		i := 0
+		for _, p := range s.PrimesInRange(lo, hi) {
+			for i < len(listed) && listed[i] < p {
+				i++
+			}
+			if i == len(listed) || listed[i] != p {
+				bad = append(bad, p)
+			}
+		}
+	}
+
+	return bad, len(bad) == 0
+}
+
+// verifyListSpan - the widest range of values VerifyPrimeList sieves to look for missing primes
+const verifyListSpan = 1 << 24
+
+// maxWilsonPrime - the largest p for which p^2 still fits in an int64
+const maxWilsonPrime = 3037000499
+
+// IsWilsonPrime - reports whether p is a Wilson prime, a prime where (p-1)! = -1 (mod p^2). Only 5, 13 and 563 are
+// known. Primes above maxWilsonPrime, whose square doesn't fit in an int64, are reported as false
+func (s *PrimeNumberSieve) IsWilsonPrime(p int64) bool {
+	if p > maxWilsonPrime || !s.IsPrime(p) {
+		return false
+	}
+
+	m := p * p
+	factorial := int64(1)
+	for k := int64(2); k < p; k++ {
+		factorial = mulmod(factorial, k, m)
+	}
+	return factorial == m-1
+}
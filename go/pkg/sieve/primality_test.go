@@ -0,0 +1,261 @@
+package sieve
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsPrime(t *testing.T) {
+	// every path answers alike: the small primes table, the cache, Miller-Rabin and its memoized results
+	cached := NewPrimeNumberSieve()
+	cached.EnsurePrimesUpTo(30000000)
+	for _, sieve := range []*PrimeNumberSieve{NewPrimeNumberSieve(), NewPrimeNumberSieve(WithPrimalityCache(64)), cached} {
+		for _, n := range []int64{math.MinInt64, -7, -2, -1, 0, 1, 4, 9, 15, 25, 49, 100, 7919 * 7919} {
+			assert.False(t, sieve.IsPrime(n), "%d should not be prime", n)
+		}
+		for _, n := range []int64{2, 3, 5, 7, 71, 541, 7919, 15485867} {
+			assert.True(t, sieve.IsPrime(n), "%d should be prime", n)
+		}
+
+		// strong pseudoprimes to the smallest bases, twice to go through any memoized result
+		for range 2 {
+			for _, n := range []int64{2047, 1373653, 25326001, 3215031751, 1000003 * 1000033} {
+				assert.False(t, sieve.IsPrime(n), "%d should not be prime", n)
+			}
+		}
+	}
+}
+
+func TestIsPrimeSmallTable(t *testing.T) {
+	primes := trueInitializedSieve(100)
+	sieve := NewPrimeNumberSieve()
+
+	for n := int64(0); n <= 100; n++ {
+		i := sort.Search(len(primes), func(i int) bool { return primes[i] >= n })
+		assert.Equal(t, i < len(primes) && primes[i] == n, sieve.IsPrime(n), "n=%d", n)
+	}
+
+	// every n from 2 was a hit on the table, none needed Miller-Rabin (a miss) and nothing was sieved into the cache
+	hits, misses := sieve.CacheStats()
+	assert.Equal(t, int64(99), hits)
+	assert.Equal(t, int64(0), misses)
+	assert.Zero(t, sieve.cache.bound)
+
+	// the table is authoritative right up to its bound, past which Miller-Rabin takes over
+	assert.True(t, sieve.IsPrime(smallPrimesBound))
+	assert.False(t, sieve.IsPrime(smallPrimesBound+1))
+	hits, misses = sieve.CacheStats()
+	assert.Equal(t, int64(100), hits)
+	assert.Equal(t, int64(1), misses)
+}
+
+func TestIsPrimeLargeValues(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// the known nth primes from TestNthPrime, none of which need sieving to test
+	for _, n := range []int64{7927, 17393, 15485867, 179424691, 2038074751, 9223372036854775783} {
+		assert.True(t, sieve.IsPrime(n), "%d should be prime", n)
+	}
+	assert.False(t, sieve.IsPrime(1000003*1000033))
+	assert.False(t, sieve.IsPrime(2038074751*3))
+	assert.False(t, sieve.IsPrime(math.MaxInt64))
+	assert.Zero(t, sieve.cache.bound)
+
+	// Carmichael numbers fool a Fermat test for every coprime base
+	for _, n := range []int64{561, 1105, 1729, 2465, 2821, 6601, 8911, 41041, 825265, 321197185, 5394826801, 232250619601, 9746347772161} {
+		assert.False(t, sieve.IsPrime(n), "%d is a Carmichael number", n)
+	}
+}
+
+func TestIsPrimeMatchesSieve(t *testing.T) {
+	primes := (&segmentedSieve{}).sieve(100000)
+	isPrime := make(map[int64]bool, len(primes))
+	for _, p := range primes {
+		isPrime[p] = true
+	}
+
+	// once through Miller-Rabin, then again through the cache
+	sieve := NewPrimeNumberSieve()
+	for pass := 0; pass < 2; pass++ {
+		for n := int64(-1); n <= 100000; n++ {
+			if sieve.IsPrime(n) != isPrime[n] {
+				assert.Fail(t, "IsPrime disagrees with the sieve", "n=%d pass=%d", n, pass)
+				break
+			}
+		}
+		sieve.EnsurePrimesUpTo(100000)
+	}
+}
+
+func TestIsqrt(t *testing.T) {
+	assert.Equal(t, int64(0), isqrt(0))
+	assert.Equal(t, int64(1), isqrt(3))
+	assert.Equal(t, int64(2), isqrt(4))
+	assert.Equal(t, int64(99), isqrt(9999))
+	assert.Equal(t, int64(100), isqrt(10000))
+	assert.Equal(t, int64(3037000499), isqrt(math.MaxInt64))
+}
+
+func TestVerifyPrimeList(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	bad, ok := sieve.VerifyPrimeList([]int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29})
+	assert.True(t, ok)
+	assert.Empty(t, bad)
+
+	bad, ok = sieve.VerifyPrimeList([]int64{2, 3, 5, 7, 9, 11})
+	assert.False(t, ok)
+	assert.Equal(t, []int64{9}, bad)
+
+	// ranges don't have to start at 2
+	bad, ok = sieve.VerifyPrimeList([]int64{101, 103, 107, 109})
+	assert.True(t, ok)
+	assert.Empty(t, bad)
+
+	bad, ok = sieve.VerifyPrimeList([]int64{})
+	assert.True(t, ok)
+	assert.Empty(t, bad)
+}
+
+func TestVerifyPrimeListOrderAndCompleteness(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// 7 is missing
+	bad, ok := sieve.VerifyPrimeList([]int64{2, 3, 5, 11})
+	assert.False(t, ok)
+	assert.Equal(t, []int64{7}, bad)
+
+	// duplicates and out of order entries are flagged, 5 is first seen out of order so is also reported missing
+	bad, ok = sieve.VerifyPrimeList([]int64{2, 3, 3, 7, 5, 11})
+	assert.False(t, ok)
+	assert.Equal(t, []int64{3, 5, 5}, bad)
+
+	bad, ok = sieve.VerifyPrimeList([]int64{-3, 1, 2})
+	assert.False(t, ok)
+	assert.Equal(t, []int64{-3, 1}, bad)
+}
+
+func TestVerifyPrimeListWideRange(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// entries spanning the whole int64 range are each tested rather than sieving between them
+	bad, ok := sieve.VerifyPrimeList([]int64{2, 9223372036854775783})
+	assert.True(t, ok)
+	assert.Empty(t, bad)
+
+	bad, ok = sieve.VerifyPrimeList([]int64{math.MinInt64, 2, 9223372036854775781, 9223372036854775783})
+	assert.False(t, ok)
+	assert.Equal(t, []int64{math.MinInt64, 9223372036854775781}, bad)
+
+	// up to the span limit missing primes are still found, 16777213 is the largest prime below 2^24
+	bad, ok = sieve.VerifyPrimeList([]int64{2, 3, 5, 16777213})
+	assert.False(t, ok)
+	assert.Equal(t, []int64{7, 11}, bad[:2])
+	assert.Len(t, bad, int(sieve.PrimeCount(16777213)-4))
+
+	// a narrow list just below math.MaxInt64 is searched for missing primes too, without stepping past an int64
+	bad, ok = sieve.VerifyPrimeList([]int64{9223372036854775783})
+	assert.True(t, ok)
+	assert.Empty(t, bad)
+	bad, ok = sieve.VerifyPrimeList([]int64{9223372036854775549, 9223372036854775643, 9223372036854775783})
+	assert.True(t, ok)
+	assert.Empty(t, bad)
+	bad, ok = sieve.VerifyPrimeList([]int64{9223372036854775549, 9223372036854775783})
+	assert.False(t, ok)
+	assert.Equal(t, []int64{9223372036854775643}, bad)
+}
+
+func TestIsPrimeSmallPrimes(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	for n := int64(0); n <= smallPrimesBound+10; n++ {
+		assert.Equal(t, n >= 2 && sieve.SmallestFactor(n) == n, sieve.IsPrime(n), "n=%d", n)
+	}
+}
+
+func TestIsWilsonPrime(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.True(t, sieve.IsWilsonPrime(5))
+	assert.True(t, sieve.IsWilsonPrime(13))
+	assert.True(t, sieve.IsWilsonPrime(563))
+	assert.False(t, sieve.IsWilsonPrime(7))
+
+	// they're the only ones, at least among the first few thousand primes
+	wilson := make([]int64, 0)
+	for _, p := range sieve.PrimesUpTo(20000) {
+		if sieve.IsWilsonPrime(p) {
+			wilson = append(wilson, p)
+		}
+	}
+	assert.Equal(t, []int64{5, 13, 563}, wilson)
+
+	// composites, and values too large to square, are never Wilson primes
+	assert.False(t, sieve.IsWilsonPrime(1))
+	assert.False(t, sieve.IsWilsonPrime(25))
+	assert.False(t, sieve.IsWilsonPrime(maxWilsonPrime+2))
+}
+
+func TestMillerRabin(t *testing.T) {
+	primes := (&segmentedSieve{}).sieve(100000)
+	isPrime := make(map[int64]bool, len(primes))
+	for _, p := range primes {
+		isPrime[p] = true
+	}
+	for n := int64(-1); n <= 100000; n++ {
+		if millerRabin(n) != isPrime[n] {
+			assert.Fail(t, "Miller-Rabin disagrees with the sieve", "n=%d", n)
+			break
+		}
+	}
+
+	// strong pseudoprimes to the smaller bases, and values near the top of an int64
+	for _, n := range []int64{2047, 3215031751, 3825123056546413051, 1000003 * 1000033, math.MaxInt64} {
+		assert.False(t, millerRabin(n), "n=%d", n)
+	}
+	for _, n := range []int64{15485867, 2038074751, 9223372036854775783} {
+		assert.True(t, millerRabin(n), "n=%d", n)
+	}
+}
+
+func TestIsPrimeChecked(t *testing.T) {
+	sieve := NewPrimeNumberSieve(WithCrossCheck(true))
+	sieve.EnsurePrimesUpTo(200000)
+
+	for n := int64(-1); n <= 200000; n++ {
+		prime, err := sieve.IsPrimeChecked(n)
+		if !assert.NoError(t, err, "n=%d", n) || !assert.Equal(t, sieve.IsPrime(n), prime, "n=%d", n) {
+			break
+		}
+	}
+
+	// beyond the sieve there's nothing to cross check against
+	prime, err := sieve.IsPrimeChecked(15485867)
+	assert.NoError(t, err)
+	assert.True(t, prime)
+}
+
+func TestIsPrimeCheckedFaultyOracle(t *testing.T) {
+	sieve := NewPrimeNumberSieve(WithCrossCheck(true))
+	sieve.EnsurePrimesUpTo(10000)
+	sieve.probablePrime = func(n int64) bool { return n == 7917 || millerRabin(n) }
+
+	prime, err := sieve.IsPrimeChecked(7917)
+	assert.False(t, prime)
+	assert.True(t, errors.Is(err, ErrCrossCheckFailed))
+
+	// values the oracle agrees on are unaffected
+	prime, err = sieve.IsPrimeChecked(7919)
+	assert.NoError(t, err)
+	assert.True(t, prime)
+
+	// and without cross checking the oracle is never consulted
+	sieve.crossCheck = false
+	prime, err = sieve.IsPrimeChecked(7917)
+	assert.NoError(t, err)
+	assert.False(t, prime)
+}
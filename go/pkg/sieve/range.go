@@ -0,0 +1,242 @@
+package sieve
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// PrimesInRange - returns every prime p where lo <= p <= hi in ascending order.
+// Only the requested window is sieved (unless it's already cached), or for a window far narrower than sqrt(hi) each number
+// in it is tested by Miller-Rabin instead. Any lo below 2 is treated as 2
+func (s *PrimeNumberSieve) PrimesInRange(lo, hi int64) []int64 {
+	if lo < 2 {
+		lo = 2
+	}
+	if hi < lo {
+		return make([]int64, 0)
+	}
+
+	if cached, ok := s.cachedRange(lo, hi); ok {
+		return cached
+	}
+	if narrowWindow(lo, hi) {
+		result := make([]int64, 0)
+		forEachTestedPrime(lo, hi, func(p int64) {
+			result = append(result, p)
+		})
+		return result
+	}
+
+	// primes up to sqrt(hi) are enough to mark every composite in the window
+	baseLimit := isqrt(hi)
+	primes := (&basicSieveOfEratosthenes{}).sieve(baseLimit)
+
+	// any part of the window at or below sqrt(hi) is already covered by the base primes
+	result := make([]int64, 0)
+	for _, p := range primes {
+		if p >= lo {
+			result = append(result, p)
+		}
+	}
+	if lo <= baseLimit {
+		lo = baseLimit + 1
+	}
+
+	// sieve the rest of the window in segments of sqrt(hi)
+	forEachSegment(primes, lo, hi, baseLimit, func(low int64, segment []bool) bool {
+		result = collectSegment(segment, low, result)
+		return true
+	})
+
+	return result
+}
+
+// narrowWindowRatio - testing a number by Miller-Rabin takes around half a microsecond, sieving the base primes a few
+// nanoseconds a number up to sqrt(hi), so windows narrower than sqrt(hi)/narrowWindowRatio are cheaper to test
+const narrowWindowRatio = 64
+
+// narrowWindow - reports whether [lo, hi] is narrow enough to test each number in it rather than sieve the base primes,
+// which near the top of an int64 would take gigabytes
+func narrowWindow(lo, hi int64) bool {
+	return hi-lo < isqrt(hi)/narrowWindowRatio
+}
+
+// forEachTestedPrime - passes each prime in [lo, hi] to fn in ascending order, testing every number by Miller-Rabin.
+// Stops at hi without stepping past it, hi may be math.MaxInt64
+func forEachTestedPrime(lo, hi int64, fn func(prime int64)) {
+	for n := lo; ; n++ {
+		if millerRabin(n) {
+			fn(n)
+		}
+		if n == hi {
+			return
+		}
+	}
+}
+
+// PrimeRange - an alias of PrimesInRange, returning the primes in [low, high] under the name the windowed API asked for.
+// It has no implementation of its own, every behaviour, including clamping low to 2, is PrimesInRange's
+func (s *PrimeNumberSieve) PrimeRange(low, high int64) []int64 {
+	return s.PrimesInRange(low, high)
+}
+
+// PrimesNear - returns every prime within tolerance of center, i.e. in [center-tolerance, center+tolerance].
+// The window is clamped to the int64 range, and a negative tolerance returns an empty slice
+func (s *PrimeNumberSieve) PrimesNear(center, tolerance int64) []int64 {
+	if tolerance < 0 {
+		return make([]int64, 0)
+	}
+
+	lo, hi := center-tolerance, center+tolerance
+	if center < math.MinInt64+tolerance {
+		lo = math.MinInt64
+	}
+	if center > math.MaxInt64-tolerance {
+		hi = math.MaxInt64
+	}
+	return s.PrimesInRange(lo, hi)
+}
+
+// PrimesUpTo - returns every prime up to and including n in ascending order, or an empty slice if n < 2.
+// The returned slice is a copy that the caller is free to modify
+func (s *PrimeNumberSieve) PrimesUpTo(n int64) []int64 {
+	view := s.PrimesUpToShared(n)
+	res := make([]int64, view.Len())
+	copy(res, view.primes)
+	return res
+}
+
+// PrimesUpToWithDeadline - returns every prime up to and including n in ascending order, sieving for at most d.
+// If the deadline passes first the primes found so far are returned with complete set to false, every one of them is
+// prime and none below the last are missing. Like PrimesInRange nothing sieved here is cached
+func (s *PrimeNumberSieve) PrimesUpToWithDeadline(n int64, d time.Duration) (primes []int64, complete bool) {
+	deadline := time.Now().Add(d)
+	if n < 2 {
+		return make([]int64, 0), true
+	}
+	if cached, ok := s.cachedRange(2, n); ok {
+		return cached, true
+	}
+
+	// the base primes up to sqrt(n) are always included, the deadline is checked between every segment after them
+	baseLimit := isqrt(n)
+	primes = (&basicSieveOfEratosthenes{}).sieve(baseLimit)
+
+	complete = true
+	forEachSegment(primes, baseLimit+1, n, baseLimit, func(low int64, segment []bool) bool {
+		primes = collectSegment(segment, low, primes)
+		// the deadline passing during the final segment still completes the list
+		if low+int64(len(segment))-1 < n && time.Now().After(deadline) {
+			complete = false
+		}
+		return complete
+	})
+	return primes, complete
+}
+
+// PrimesUpToShared - returns a read-only view of every prime up to and including n, avoiding the copy made by PrimesUpTo.
+// WARNING: the view shares memory with the sieve's cache, it must not be converted back into a mutable slice
+func (s *PrimeNumberSieve) PrimesUpToShared(n int64) PrimeView {
+	if n < 2 {
+		return PrimeView{}
+	}
+
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	s.extendToValue(n)
+	primes := s.cache.primes
+	count := sort.Search(len(primes), func(i int) bool { return primes[i] > n })
+
+	// the cache only ever replaces or appends to its slice, so this prefix can't change underneath the view
+	return PrimeView{primes: primes[:count:count]}
+}
+
+// PrimePi - the prime counting function pi(x), returning how many primes are <= x
+func (s *PrimeNumberSieve) PrimePi(x int64) int64 {
+	if x < 2 {
+		return 0
+	}
+
+	s.cache.record(s.coversValue(x))
+	return int64(s.PrimesUpToShared(x).Len())
+}
+
+// PrimeCount - the prime counting function pi(n), like PrimePi but counting the primes as they're sieved instead of
+// collecting and caching them, so a large n never allocates the list of every prime below it
+func (s *PrimeNumberSieve) PrimeCount(n int64) int64 {
+	if n < 2 {
+		return 0
+	}
+
+	s.cache.record(s.coversValue(n))
+	return s.countPrimesInRange(2, n)
+}
+
+// CountPrimesInRange - returns how many primes p there are where lo <= p <= hi, sieving only the window like
+// PrimesInRange but counting the primes rather than collecting them. Any lo below 2 is treated as 2
+func (s *PrimeNumberSieve) CountPrimesInRange(lo, hi int64) int64 {
+	return s.countPrimesInRange(lo, hi)
+}
+
+// countPrimesInRange - how many primes p there are where lo <= p <= hi, counted a segment at a time by ReducePrimes
+func (s *PrimeNumberSieve) countPrimesInRange(lo, hi int64) int64 {
+	return ReducePrimes(s, lo, hi, 0, func(count int64, _ int64) int64 { return count + 1 })
+}
+
+// FirstIndexAbove - the smallest index n where the nth prime is > threshold, the inverse of NthPrime.
+// With 0-based indexing this is the number of primes <= threshold, pi(threshold)
+func (s *PrimeNumberSieve) FirstIndexAbove(threshold int64) int64 {
+	return s.PrimePi(threshold)
+}
+
+// PrimeView - a read-only view of a list of primes in ascending order
+type PrimeView struct {
+	primes []int64
+}
+
+// Len - the number of primes in the view
+func (v PrimeView) Len() int {
+	return len(v.primes)
+}
+
+// At - returns the ith prime in the view, panics if i is out of range like a slice index would
+func (v PrimeView) At(i int) int64 {
+	return v.primes[i]
+}
+
+// Copy - returns the primes in the view as a new slice the caller is free to modify
+func (v PrimeView) Copy() []int64 {
+	res := make([]int64, len(v.primes))
+	copy(res, v.primes)
+	return res
+}
+
+// cachedRange - returns a copy of the cached primes within [lo, hi] if the cache covers hi
+func (s *PrimeNumberSieve) cachedRange(lo, hi int64) ([]int64, bool) {
+	shared, ok := s.cachedWindow(lo, hi)
+	if !ok {
+		return nil, false
+	}
+
+	res := make([]int64, len(shared))
+	copy(res, shared)
+	return res, true
+}
+
+// cachedWindow - returns the cached primes within [lo, hi] if the cache covers hi, sharing the cache's backing array
+// so it must not be modified. Extending the cache only ever appends past the primes already in it, so this stays valid
+func (s *PrimeNumberSieve) cachedWindow(lo, hi int64) ([]int64, bool) {
+	s.cache.mu.Lock()
+	defer s.cache.mu.Unlock()
+
+	if hi > s.cache.bound {
+		return nil, false
+	}
+
+	primes := s.cache.primes
+	from := sort.Search(len(primes), func(i int) bool { return primes[i] >= lo })
+	to := sort.Search(len(primes), func(i int) bool { return primes[i] > hi })
+	return primes[from:to:to], true
+}
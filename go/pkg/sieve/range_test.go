@@ -0,0 +1,297 @@
+package sieve
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrimesInRange(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, []int64{2, 3, 5, 7}, sieve.PrimesInRange(-10, 10))
+	assert.Equal(t, []int64{2, 3, 5, 7}, sieve.PrimesInRange(-100, 10))
+	assert.Equal(t, []int64{}, sieve.PrimesInRange(-5, -1))
+	assert.Equal(t, []int64{2}, sieve.PrimesInRange(0, 2))
+	assert.Equal(t, []int64{}, sieve.PrimesInRange(10, 5))
+	assert.Equal(t, []int64{101, 103, 107, 109, 113, 127}, sieve.PrimesInRange(100, 130))
+	assert.Equal(t, []int64{7919}, sieve.PrimesInRange(7919, 7919))
+}
+
+func TestPrimeRange(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, []int64{1000003, 1000033, 1000037, 1000039, 1000081, 1000099}, sieve.PrimeRange(1000000, 1000100))
+	assert.Equal(t, []int64{2, 3, 5}, sieve.PrimeRange(2, 6))
+
+	// low <= 2 is clamped to 2
+	assert.Equal(t, []int64{2, 3, 5}, sieve.PrimeRange(-3, 6))
+	assert.Equal(t, []int64{2}, sieve.PrimeRange(0, 2))
+	assert.Equal(t, []int64{2}, sieve.PrimeRange(2, 2))
+	assert.Equal(t, []int64{}, sieve.PrimeRange(0, 1))
+	assert.Equal(t, []int64{}, sieve.PrimeRange(math.MinInt64, -1))
+
+	// low > high is empty, never nil
+	assert.Equal(t, []int64{}, sieve.PrimeRange(100, 10))
+	assert.Equal(t, []int64{}, sieve.PrimeRange(3, 2))
+	assert.NotNil(t, sieve.PrimeRange(1000040, 1000010))
+
+	// a window well inside a single segment of sqrt(high)
+	assert.Equal(t, []int64{1000033, 1000037, 1000039}, sieve.PrimeRange(1000010, 1000040))
+	assert.Equal(t, []int64{1000003}, sieve.PrimeRange(1000003, 1000003))
+
+	// without sieving, let alone caching, anything below low
+	assert.Zero(t, sieve.cache.bound)
+
+	// once cached, windows inside the cache agree with the sieved ones
+	sieve.EnsurePrimesUpTo(1000100)
+	assert.Equal(t, []int64{1000033, 1000037, 1000039}, sieve.PrimeRange(1000010, 1000040))
+	assert.Equal(t, sieve.PrimesInRange(-5, 100), sieve.PrimeRange(-5, 100))
+}
+
+func TestPrimesInRangeMatchesSegmentedSieve(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	all := (&segmentedSieve{}).sieve(100000)
+
+	var want []int64
+	for _, p := range all {
+		if p >= 5000 && p <= 100000 {
+			want = append(want, p)
+		}
+	}
+	assert.Equal(t, want, sieve.PrimesInRange(5000, 100000))
+
+	// once cached the same range is served from the cache
+	sieve.Prewarm(int64(len(all)) + 1000)
+	assert.GreaterOrEqual(t, sieve.cache.bound, int64(100000))
+	assert.Equal(t, want, sieve.PrimesInRange(5000, 100000))
+}
+
+func TestPrimesInRangeNarrowWindows(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// a window too narrow to be worth sieving the base primes for is tested a number at a time, with the same result
+	lo := int64(1000000000000)
+	assert.True(t, narrowWindow(lo, lo+10000))
+	assert.False(t, narrowWindow(lo, lo+20000))
+	var want []int64
+	for _, p := range sieve.PrimesInRange(lo, lo+20000) {
+		if p <= lo+10000 {
+			want = append(want, p)
+		}
+	}
+	assert.Equal(t, want, sieve.PrimesInRange(lo, lo+10000))
+
+	// which keeps windows at the top of an int64 practical
+	assert.True(t, narrowWindow(math.MaxInt64-1000, math.MaxInt64))
+	assert.Equal(t, []int64{9223372036854775783}, sieve.PrimesInRange(math.MaxInt64-100, math.MaxInt64))
+	assert.Equal(t, []int64{}, sieve.PrimesInRange(math.MaxInt64, math.MaxInt64))
+}
+
+func TestForEachSegmentTopOfInt64(t *testing.T) {
+	// with only the primes up to 1000 the window isn't fully sieved, but each number left must have no factor among
+	// them, and marking mustn't step past an int64 in a segment ending at math.MaxInt64
+	primes := (&basicSieveOfEratosthenes{}).sieve(1000)
+	unmarked := func(n int64) bool {
+		for _, p := range primes {
+			if n%p == 0 {
+				return false
+			}
+		}
+		return true
+	}
+
+	last := int64(math.MaxInt64 - 1001)
+	forEachSegment(primes, last+1, math.MaxInt64, 97, func(low int64, segment []bool) bool {
+		assert.Equal(t, last+1, low)
+		for i, isPrime := range segment {
+			assert.Equal(t, unmarked(low+int64(i)), isPrime, "n=%d", low+int64(i))
+		}
+		last = low + int64(len(segment)-1)
+		return true
+	})
+	assert.Equal(t, int64(math.MaxInt64), last)
+
+	// the same at the top of an int32
+	segment := markSegment(toWidth[int32](primes), math.MaxInt32-100, math.MaxInt32)
+	for i, isPrime := range segment {
+		assert.Equal(t, unmarked(math.MaxInt32-100+int64(i)), isPrime, "n=%d", math.MaxInt32-100+i)
+	}
+}
+
+func TestPrimesUpTo(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, []int64{}, sieve.PrimesUpTo(-1))
+	assert.Equal(t, []int64{}, sieve.PrimesUpTo(1))
+	assert.Equal(t, []int64{2}, sieve.PrimesUpTo(2))
+	assert.Equal(t, []int64{2, 3, 5, 7, 11, 13, 17, 19}, sieve.PrimesUpTo(20))
+	assert.Len(t, sieve.PrimesUpTo(1000000), 78498)
+
+	// smaller queries are served from the already extended cache
+	assert.Equal(t, []int64{2, 3, 5, 7}, sieve.PrimesUpTo(10))
+}
+
+func TestPrimesUpToSortedAndUnique(t *testing.T) {
+	for _, algorithm := range []Algorithm{AlgorithmSegmented, AlgorithmBasic, AlgorithmAtkin, AlgorithmWheel} {
+		sieve := NewPrimeNumberSieve(WithAlgorithm(algorithm))
+
+		// empty results are non-nil, for callers that distinguish the two
+		assert.NotNil(t, sieve.PrimesUpTo(0))
+		assert.NotNil(t, sieve.PrimesUpTo(math.MinInt64))
+
+		primes := sieve.PrimesUpTo(250000)
+		for i := 1; i < len(primes); i++ {
+			if primes[i] <= primes[i-1] {
+				assert.Fail(t, "primes out of order", "algorithm %d: %d follows %d", algorithm, primes[i], primes[i-1])
+				break
+			}
+		}
+		assert.Len(t, primes, 22044)
+		assert.Equal(t, sieve.NthPrime(22043), primes[len(primes)-1])
+	}
+}
+
+func TestPrimesUpToReturnsCopy(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	primes := sieve.PrimesUpTo(30)
+	for i := range primes {
+		primes[i] = 4
+	}
+
+	assert.Equal(t, []int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}, sieve.PrimesUpTo(30))
+	assert.Equal(t, int64(29), sieve.NthPrime(9))
+}
+
+func TestPrimesUpToShared(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, 0, sieve.PrimesUpToShared(1).Len())
+
+	view := sieve.PrimesUpToShared(30)
+	assert.Equal(t, 10, view.Len())
+	assert.Equal(t, int64(2), view.At(0))
+	assert.Equal(t, int64(29), view.At(9))
+
+	// extending the cache leaves existing views untouched
+	sieve.PrimesUpTo(100000)
+	assert.Equal(t, 10, view.Len())
+
+	primes := view.Copy()
+	primes[0] = 4
+	assert.Equal(t, int64(2), view.At(0))
+	assert.Equal(t, sieve.PrimesUpTo(30), view.Copy())
+}
+
+func TestPrimesNear(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, []int64{97, 101, 103, 107, 109}, sieve.PrimesNear(100, 10))
+	assert.Equal(t, []int64{2, 3, 5}, sieve.PrimesNear(0, 5))
+	assert.Equal(t, []int64{7919}, sieve.PrimesNear(7919, 0))
+	assert.Equal(t, []int64{}, sieve.PrimesNear(100, -1))
+	assert.Equal(t, []int64{}, sieve.PrimesNear(math.MinInt64, 10))
+
+	// the window is clamped at the top of an int64 too
+	assert.Equal(t, []int64{}, sieve.PrimesNear(math.MaxInt64, 10))
+	assert.Equal(t, []int64{9223372036854775643, 9223372036854775783}, sieve.PrimesNear(math.MaxInt64, 200))
+	assert.Equal(t, []int64{9223372036854775783}, sieve.PrimesNear(9223372036854775783, 100))
+}
+
+func TestPrimePi(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, int64(0), sieve.PrimePi(-10))
+	assert.Equal(t, int64(0), sieve.PrimePi(1))
+	assert.Equal(t, int64(1), sieve.PrimePi(2))
+	assert.Equal(t, int64(4), sieve.PrimePi(10))
+	assert.Equal(t, int64(25), sieve.PrimePi(100))
+	assert.Equal(t, int64(78498), sieve.PrimePi(1000000))
+}
+
+func TestPrimeCount(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	for n, want := range map[int64]int64{
+		-10: 0, 0: 0, 1: 0, 2: 1, 3: 2, 10: 4, 100: 25, 1000: 168, 10000: 1229, 100000: 9592, 1000000: 78498,
+		10000000: 664579,
+	} {
+		assert.Equal(t, want, sieve.PrimeCount(n), "n=%d", n)
+	}
+
+	// counting caches nothing, while the same count from the cache once it covers n agrees
+	assert.Zero(t, sieve.cache.bound)
+	assert.Equal(t, sieve.PrimePi(100000), sieve.PrimeCount(100000))
+	assert.Equal(t, int64(9592), sieve.PrimeCount(100000))
+}
+
+func TestCountPrimesInRange(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	for _, r := range [][2]int64{{-10, 10}, {2, 2}, {10, 30}, {30, 10}, {24, 28}, {1000000, 1000100}, {999000, 2000000}} {
+		assert.Equal(t, int64(len(sieve.PrimesInRange(r[0], r[1]))), sieve.CountPrimesInRange(r[0], r[1]), "range %v", r)
+	}
+	assert.Equal(t, int64(6), sieve.CountPrimesInRange(1000000, 1000100))
+}
+
+func BenchmarkPrimeCount(b *testing.B) {
+	n := int64(10000000)
+
+	b.Run("PrimesUpTo", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_ = len(NewPrimeNumberSieve().PrimesUpTo(n))
+		}
+	})
+
+	b.Run("PrimeCount", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			NewPrimeNumberSieve().PrimeCount(n)
+		}
+	})
+}
+
+func TestFirstIndexAbove(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	// p_4 = 11 > 10 and p_3 = 7 <= 10
+	assert.Equal(t, int64(4), sieve.FirstIndexAbove(10))
+	assert.Equal(t, int64(0), sieve.FirstIndexAbove(1))
+	assert.Equal(t, int64(0), sieve.FirstIndexAbove(-5))
+	assert.Equal(t, int64(1), sieve.FirstIndexAbove(2))
+
+	for _, threshold := range []int64{3, 100, 7918, 7919, 104729} {
+		n := sieve.FirstIndexAbove(threshold)
+		assert.Greater(t, sieve.NthPrime(n), threshold)
+		if n > 0 {
+			assert.LessOrEqual(t, sieve.NthPrime(n-1), threshold)
+		}
+	}
+}
+
+func TestPrimesUpToWithDeadline(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	primes, complete := sieve.PrimesUpToWithDeadline(1000000, time.Minute)
+	assert.True(t, complete)
+	assert.Equal(t, sieve.PrimesUpTo(1000000), primes)
+
+	primes, complete = sieve.PrimesUpToWithDeadline(1, time.Minute)
+	assert.True(t, complete)
+	assert.Equal(t, []int64{}, primes)
+
+	// a deadline that has already passed still returns a valid, if partial, prefix of the primes
+	primes, complete = NewPrimeNumberSieve().PrimesUpToWithDeadline(1000000000, time.Nanosecond)
+	assert.False(t, complete)
+	assert.NotEmpty(t, primes)
+	assert.Equal(t, sieve.PrimesUpTo(primes[len(primes)-1]), primes)
+
+	// while a query the cache covers completes regardless
+	primes, complete = sieve.PrimesUpToWithDeadline(1000, 0)
+	assert.True(t, complete)
+	assert.Equal(t, sieve.PrimesUpTo(1000), primes)
+}
@@ -0,0 +1,185 @@
+package sieve
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// PierpontPrimes - returns all primes of the form 2^u * 3^v + 1 that are less than or equal to limit, in ascending order
+func (s *PrimeNumberSieve) PierpontPrimes(limit int64) []int64 {
+	res := make([]int64, 0)
+	if limit < 2 {
+		return res
+	}
+
+	// generate every 2^u * 3^v <= limit - 1, checking before each multiplication so nothing can overflow
+	maxSmooth := limit - 1
+	for pow2 := int64(1); ; pow2 *= 2 {
+		for smooth := pow2; ; smooth *= 3 {
+			if s.IsPrime(smooth + 1) {
+				res = append(res, smooth+1)
+			}
+			if smooth > maxSmooth/3 {
+				break
+			}
+		}
+		if pow2 > maxSmooth/2 {
+			break
+		}
+	}
+
+	// values are generated by power of 2 first, so they need sorting to be returned in order
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+// FibonacciPrimes - returns all Fibonacci numbers less than or equal to limit that are prime, in ascending order
+func (s *PrimeNumberSieve) FibonacciPrimes(limit int64) []int64 {
+	res := make([]int64, 0)
+
+	// walk the sequence 1, 2, 3, 5, 8, ... stopping once the next value would pass limit (or overflow)
+	for prev, curr := int64(1), int64(2); curr <= limit; {
+		if s.IsPrime(curr) {
+			res = append(res, curr)
+		}
+		if prev > limit-curr {
+			break
+		}
+		prev, curr = curr, prev+curr
+	}
+
+	return res
+}
+
+// RamanujanPrimes - returns every Ramanujan prime <= limit in ascending order. The nth Ramanujan prime R_n is the
+// smallest number such that pi(x) - pi(x/2) >= n for every x >= R_n, making R_1 = 2, R_2 = 11, R_3 = 17, ...
+func (s *PrimeNumberSieve) RamanujanPrimes(limit int64) []int64 {
+	res := make([]int64, 0)
+
+	// R_n > p_2n for n > 1, so only n up to about pi(limit)/2 can have R_n <= limit
+	maxN := (s.PrimePi(limit) + 1) / 2
+	if maxN == 0 {
+		return res
+	}
+
+	// and R_n < p_3n (Laishram), so beyond p_3n's bound pi(x) - pi(x/2) is already known to stay >= n
+	bound := EstimateUpperBound(3 * maxN)
+	primes := s.PrimesUpToShared(bound)
+
+	// lastBelow[c] - the largest x where pi(x) - pi(x/2) == c
+	lastBelow := make([]int64, maxN+1)
+	var pi, piHalf int64
+	for x := int64(1); x <= bound; x++ {
+		if pi < int64(primes.Len()) && primes.At(int(pi)) == x {
+			pi++
+		}
+		if piHalf < int64(primes.Len()) && primes.At(int(piHalf)) == x/2 {
+			piHalf++
+		}
+		if c := pi - piHalf; c <= maxN {
+			lastBelow[c] = x
+		}
+	}
+
+	// R_n is one past the last x where pi(x) - pi(x/2) is still below n
+	var last int64
+	for n := int64(1); n <= maxN; n++ {
+		if lastBelow[n-1] > last {
+			last = lastBelow[n-1]
+		}
+		if last+1 > limit {
+			break
+		}
+		res = append(res, last+1)
+	}
+
+	return res
+}
+
+// NthPrimeInProgression - returns the nth (0-based) prime p with p = a (mod m). Dirichlet's theorem guarantees there
+// are infinitely many when gcd(a, m) == 1, otherwise at most a itself is prime. Where there's no nth prime, or for a
+// negative n or m <= 0, the program will return 0, see PrimeInProgression for the reason as an error
+func (s *PrimeNumberSieve) NthPrimeInProgression(a, m, n int64) int64 {
+	p, _ := s.PrimeInProgression(a, m, n)
+	return p
+}
+
+// PrimeInProgression - same as NthPrimeInProgression, returning an error wrapping ErrNegativeIndex for a negative n,
+// ErrNoPrimeFound for m <= 0 or when gcd(a, m) != 1 leaves no nth prime, or ErrIndexTooLarge when the nth prime doesn't
+// fit in an int64, rather than 0
+func (s *PrimeNumberSieve) PrimeInProgression(a, m, n int64) (int64, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("%w: %d", ErrNegativeIndex, n)
+	}
+	if m <= 0 {
+		return 0, fmt.Errorf("%w: there is no progression modulo %d", ErrNoPrimeFound, m)
+	}
+	if a %= m; a < 0 {
+		a += m
+	}
+	if g := gcd(a, m); g != 1 {
+		// every term is a multiple of g, so only g itself can be prime, and only if it's the first term a
+		if n == 0 && s.IsPrime(a) {
+			return a, nil
+		}
+		return 0, fmt.Errorf("%w: %d mod %d holds at most one prime, as gcd(%d, %d) = %d", ErrNoPrimeFound, a, m, a, m, g)
+	}
+
+	// sparse progressions hold only about 1/phi(m) of the primes, so keep doubling the bound until the nth shows up
+	var count int64
+	scanned := 0
+	for upperBound := EstimateUpperBound(n); ; upperBound = doubleBound(upperBound) {
+		primes := s.PrimesUpToShared(upperBound)
+		for ; scanned < primes.Len(); scanned++ {
+			if p := primes.At(scanned); p%m == a {
+				if count == n {
+					return p, nil
+				}
+				count++
+			}
+		}
+
+		if upperBound == math.MaxInt64 {
+			return 0, fmt.Errorf("%w: the prime at index %d of %d mod %d doesn't fit in an int64", ErrIndexTooLarge, n, a, m)
+		}
+		tracef(s.trace, "n=%d not within %d primes = %d mod %d up to %d, doubling upper bound to %d",
+			n, count, a, m, upperBound, doubleBound(upperBound))
+	}
+}
+
+// ChenPrimes - returns every Chen prime <= limit in ascending order, the primes p where p + 2 is either prime or a
+// semiprime, the product of exactly two primes such as 4 = 2 * 2 or 35 = 5 * 7
+func (s *PrimeNumberSieve) ChenPrimes(limit int64) []int64 {
+	res := make([]int64, 0)
+	if limit < 2 {
+		return res
+	}
+	// so p + 2 can't overflow
+	if limit > math.MaxInt64-2 {
+		limit = math.MaxInt64 - 2
+	}
+
+	// any composite p + 2 has a prime factor no larger than its square root
+	divisors := s.PrimesUpTo(isqrt(limit + 2))
+	for _, p := range s.PrimesUpTo(limit) {
+		if s.IsPrime(p+2) || s.isSemiprime(p+2, divisors) {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// isSemiprime - reports whether n is the product of exactly two primes, counting repeats, given every prime up to
+// sqrt(n). n is a semiprime when dividing out its smallest factor leaves a prime
+func (s *PrimeNumberSieve) isSemiprime(n int64, divisors []int64) bool {
+	for _, q := range divisors {
+		if q > n/q {
+			break
+		}
+		if n%q == 0 {
+			return s.IsPrime(n / q)
+		}
+	}
+	return false
+}
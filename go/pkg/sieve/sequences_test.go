@@ -0,0 +1,123 @@
+package sieve
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPierpontPrimes(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, []int64{2, 3, 5, 7, 13, 17, 19, 37, 73, 97}, sieve.PierpontPrimes(100))
+	assert.Equal(t, []int64{2, 3, 5, 7, 13, 17, 19, 37, 73, 97, 109, 163}, sieve.PierpontPrimes(163))
+	assert.Equal(t, []int64{2}, sieve.PierpontPrimes(2))
+	assert.Equal(t, []int64{}, sieve.PierpontPrimes(1))
+	assert.Equal(t, []int64{}, sieve.PierpontPrimes(-10))
+}
+
+func TestFibonacciPrimes(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, []int64{2, 3, 5, 13, 89}, sieve.FibonacciPrimes(100))
+	assert.Equal(t, []int64{2, 3, 5, 13, 89, 233, 1597}, sieve.FibonacciPrimes(1597))
+	assert.Equal(t, []int64{}, sieve.FibonacciPrimes(1))
+	assert.Equal(t, []int64{2, 3, 5, 13, 89, 233, 1597, 28657, 514229, 433494437}, sieve.FibonacciPrimes(1000000000))
+}
+
+func TestRamanujanPrimes(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, []int64{2, 11, 17, 29, 41, 47, 59, 67, 71, 97}, sieve.RamanujanPrimes(100))
+	assert.Equal(t, []int64{2, 11, 17, 29, 41, 47, 59, 67, 71, 97, 101, 107, 127, 149, 151, 167, 179, 181,
+		227, 229, 233, 239, 241, 263, 269, 281, 307, 311, 347, 349, 367, 373, 401, 409, 419, 431, 433, 439,
+		461, 487, 491}, sieve.RamanujanPrimes(500))
+	assert.Equal(t, []int64{2}, sieve.RamanujanPrimes(10))
+	assert.Equal(t, []int64{2}, sieve.RamanujanPrimes(2))
+	assert.Equal(t, []int64{}, sieve.RamanujanPrimes(1))
+
+	// every Ramanujan prime is prime
+	for _, p := range sieve.RamanujanPrimes(100000) {
+		assert.True(t, sieve.IsPrime(p), "%d", p)
+	}
+}
+
+func TestNthPrimeInProgression(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, int64(5), sieve.NthPrimeInProgression(1, 4, 0))
+	assert.Equal(t, int64(3), sieve.NthPrimeInProgression(3, 4, 0))
+	assert.Equal(t, int64(13), sieve.NthPrimeInProgression(1, 4, 1))
+	assert.Equal(t, int64(2), sieve.NthPrimeInProgression(0, 1, 0))
+	assert.Equal(t, int64(3), sieve.NthPrimeInProgression(-1, 4, 0))
+
+	// a sparse progression needs several doublings: 1000003 is the first prime = 1 mod 1000002
+	assert.Equal(t, int64(1000003), sieve.NthPrimeInProgression(1, 1000002, 0))
+
+	// the progression agrees with filtering the primes directly
+	var want []int64
+	for _, p := range sieve.PrimesUpTo(100000) {
+		if p%10 == 7 {
+			want = append(want, p)
+		}
+	}
+	for n, p := range want {
+		assert.Equal(t, p, sieve.NthPrimeInProgression(7, 10, int64(n)))
+	}
+
+	// when gcd(a, m) != 1 only a itself can be prime
+	assert.Equal(t, int64(2), sieve.NthPrimeInProgression(2, 4, 0))
+	assert.Equal(t, int64(0), sieve.NthPrimeInProgression(2, 4, 1))
+	assert.Equal(t, int64(0), sieve.NthPrimeInProgression(4, 6, 0))
+
+	// invalid progressions
+	assert.Equal(t, int64(0), sieve.NthPrimeInProgression(1, 0, 0))
+	assert.Equal(t, int64(0), sieve.NthPrimeInProgression(1, 4, -1))
+}
+
+func TestPrimeInProgression(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	p, err := sieve.PrimeInProgression(1, 4, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(13), p)
+	p, err = sieve.PrimeInProgression(-2, 4, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), p)
+
+	// progressions without an nth prime say why rather than returning 0
+	for _, c := range [][3]int64{{2, 4, 1}, {4, 6, 0}, {0, 10, 0}, {1, 0, 0}, {1, -4, 0}} {
+		_, err = sieve.PrimeInProgression(c[0], c[1], c[2])
+		assert.True(t, errors.Is(err, ErrNoPrimeFound), "progression %v", c)
+	}
+	_, err = sieve.PrimeInProgression(1, 4, -1)
+	assert.True(t, errors.Is(err, ErrNegativeIndex))
+}
+
+func TestChenPrimes(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	assert.Equal(t, []int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29}, sieve.ChenPrimes(30))
+	assert.Equal(t, []int64{
+		2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37, 41, 47, 53, 59, 67, 71, 83, 89, 101, 107, 109, 113, 127, 131,
+		137, 139, 149, 157, 167, 179, 181, 191, 197, 199, 211, 227, 233, 239, 251, 257, 263, 269, 281, 293, 307, 311,
+		317, 337, 347, 353, 359, 379, 389, 401, 409,
+	}, sieve.ChenPrimes(409))
+	assert.Equal(t, []int64{}, sieve.ChenPrimes(1))
+
+	// 43 is the first prime that isn't, 45 = 3 * 3 * 5 has three factors
+	assert.NotContains(t, sieve.ChenPrimes(50), int64(43))
+}
+
+func TestIsSemiprime(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	divisors := sieve.PrimesUpTo(1000)
+
+	for _, n := range []int64{4, 6, 9, 35, 49, 1000003 * 997} {
+		assert.True(t, sieve.isSemiprime(n, divisors), "n=%d", n)
+	}
+	for _, n := range []int64{2, 7, 8, 12, 45, 1000003} {
+		assert.False(t, sieve.isSemiprime(n, divisors), "n=%d", n)
+	}
+}
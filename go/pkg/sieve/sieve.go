@@ -1,56 +1,183 @@
 package sieve
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"math"
+	"runtime"
+	"sort"
+	"sync"
 )
 
-/*
-Further Improvements:
-	- Add wheel optimization to the basic sieve of eratosthenes
-	- Implement Sieve of Atkin
-	- Add concurrency to the Segmented Sieve
-	- Implement config (and/or) parameters to choose which internal sieve to use
-*/
-
 // Sieve - provides an API for retrieving the Nth prime number using 0-based indexing where the 0th prime number is 2
 type Sieve interface {
 	NthPrime(n int64) int64
 }
 
 // PrimeNumberSieve - a struct required to implement the NthPrime Sieve interface.
-type PrimeNumberSieve struct{}
+// Primes computed by one call are cached and reused by later calls, a PrimeNumberSieve is safe for concurrent use
+type PrimeNumberSieve struct {
+	trace            io.Writer
+	memoryLimit      int64
+	verifyOnLoad     bool
+	adaptiveSegments bool
+	tracer           Tracer
+	batchConcurrency int
+	primality        *primalityCache
+	fanOutBuffer     int
+	fanOutPolicy     FanOutPolicy
+	workers          int
+	crossCheck       bool
+	debugVerify      bool
+	probablePrime    func(n int64) bool
+	newSieveFunc     func(s *PrimeNumberSieve) sieve
+	cache            *primeCache
+	closeOnce        sync.Once
+}
+
+// NewPrimeNumberSieve - Creates a new PrimeNumberSieve, configured by any provided options
+func NewPrimeNumberSieve(opts ...Option) *PrimeNumberSieve {
+	s := &PrimeNumberSieve{
+		memoryLimit:   defaultMemoryLimit,
+		tracer:        noopTracer{},
+		probablePrime: millerRabin,
+		newSieveFunc:  sieveFactories[AlgorithmSegmented],
+		cache:         &primeCache{refs: 1},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// NewSieve - Creates a new Sieve with the default configuration. Deliberately returns the Sieve interface rather than
+// *PrimeNumberSieve, like SieveBuilder.Build, so code written against it depends only on NthPrime and can swap in
+// another implementation. Use NewPrimeNumberSieve for options and the rest of the PrimeNumberSieve API
+func NewSieve() Sieve {
+	return NewPrimeNumberSieve()
+}
 
-// NewPrimeNumberSieve - Creates a new PrimeNumberSieve
-func NewPrimeNumberSieve() *PrimeNumberSieve {
-	return &PrimeNumberSieve{}
+// SieveStats - describes the work done to answer a single query
+type SieveStats struct {
+	// UpperBound - the bound the primes used to answer the query were sieved up to
+	UpperBound int64
+	// Passes - how many times the sieve had to run, 0 when the query was served entirely from cache
+	Passes int
+	// PrimesDiscovered - how many new primes were added to the cache by the query
+	PrimesDiscovered int64
+	// Fallback - true if caching the primes would have exceeded the memory limit, so they were streamed instead
+	Fallback bool
 }
 
+// maxPrimeIndex - the index of the largest prime below 2^63, pi(2^63) - 1
+const maxPrimeIndex = 216289611853439383
+
 // NthPrime - Will calculate up to the nth prime number starting at 2
-// if n is negative, the program will return 0
+// if n is negative, or too large for the nth prime to fit in an int64, the program will return 0
 func (s *PrimeNumberSieve) NthPrime(nthPrime int64) int64 {
+	prime, _ := s.PrimeAt(nthPrime)
+	return prime
+}
+
+// NthPrimeE - an alias of PrimeAt, with exactly the same contract.
+//
+// Deprecated: use PrimeAt
+func (s *PrimeNumberSieve) NthPrimeE(nthPrime int64) (int64, error) {
+	return s.PrimeAt(nthPrime)
+}
 
+// PrimeAt - the canonical errored nth prime accessor. Same as NthPrime, returning an error wrapping ErrNegativeIndex
+// for a negative index, or ErrIndexTooLarge for one whose prime doesn't fit in an int64, rather than 0 which can't be
+// told apart from a real answer
+func (s *PrimeNumberSieve) PrimeAt(nthPrime int64) (int64, error) {
+	return s.NthPrimeCtx(context.Background(), nthPrime)
+}
+
+// NthPrimeCtx - same as PrimeAt, abandoning the search with ctx.Err() once ctx is done, including while waiting for
+// another query to finish with the cache. The context is checked between every segment the segmented sieve processes,
+// the basic, Atkin and wheel sieves only check it between passes
+func (s *PrimeNumberSieve) NthPrimeCtx(ctx context.Context, nthPrime int64) (int64, error) {
 	if nthPrime < 0 {
-		return 0
+		return 0, fmt.Errorf("%w: %d", ErrNegativeIndex, nthPrime)
+	}
+	if nthPrime > maxPrimeIndex {
+		return 0, fmt.Errorf("%w: the prime at index %d doesn't fit in an int64", ErrIndexTooLarge, nthPrime)
 	}
 
-	// use segmented sieve by default
-	sieveFunc := &segmentedSieve{}
+	prime, _, err := s.nthPrimeWithStats(ctx, nthPrime)
+	return prime, err
+}
+
+// NthPrimeWithStats - same as NthPrime, also reporting the work done to find the nth prime
+func (s *PrimeNumberSieve) NthPrimeWithStats(nthPrime int64) (int64, SieveStats) {
+	prime, stats, _ := s.nthPrimeWithStats(context.Background(), nthPrime)
+	return prime, stats
+}
+
+// nthPrimeWithStats - finds the nth prime from the cache, sieving or streaming it if needed, until ctx is done
+func (s *PrimeNumberSieve) nthPrimeWithStats(ctx context.Context, nthPrime int64) (int64, SieveStats, error) {
+	span := s.tracer.Start("sieve.NthPrime")
+	defer span.End()
+	span.SetAttribute(SpanAttrIndex, nthPrime)
 
-	// Pick a good upper bound: https://en.wikipedia.org/wiki/Prime_number_theorem
-	upperBounds := nthPrime * (int64)(math.Log(float64(nthPrime)))
-	if nthPrime < 6 {
-		upperBounds = 20 // handles n <= 5 better since log is small for these
+	// beyond maxPrimeIndex the bound overflows an int64, see PrimeAt
+	if nthPrime < 0 || nthPrime > maxPrimeIndex {
+		return 0, SieveStats{}, nil
 	}
 
-	// Sieves till the upperbound and tests if the nth prime number can be found in the result
-	// If not, scale upperbound and start again
-	for {
-		res := sieveFunc.sieve(upperBounds)
-		if nthPrime <= int64(len(res)) {
-			return res[nthPrime]
-		}
-		upperBounds = upperBounds * 2
+	// waiting on another query's sieve is abandoned along with this one
+	if err := s.cache.mu.lockContext(ctx); err != nil {
+		return 0, SieveStats{}, err
+	}
+	prime, stats, err := s.nthPrimeLocked(ctx, nthPrime)
+	s.cache.mu.Unlock()
+	s.cache.record(stats.Passes == 0 && !stats.Fallback)
+
+	// streaming never touches the cache, so other queries aren't held up while it runs
+	if err == nil && stats.Fallback {
+		prime, err = streamNthPrime(ctx, nthPrime, stats.UpperBound, &stats, s.trace)
 	}
+
+	span.SetAttribute(SpanAttrUpperBound, stats.UpperBound)
+	span.SetAttribute(SpanAttrAlgorithm, s.newSieve().name())
+	span.SetAttribute(SpanAttrCached, stats.Passes == 0)
+	if err != nil {
+		return 0, stats, err
+	}
+	return prime, stats, nil
+}
+
+// NthPrimeAndBound - same as NthPrime, also returning the bound primes were sieved up to to find it, including any
+// doubling of the estimated bound. Callers can reuse the bound to size their own work, it is always >= prime
+func (s *PrimeNumberSieve) NthPrimeAndBound(nthPrime int64) (prime, boundUsed int64) {
+	prime, stats := s.NthPrimeWithStats(nthPrime)
+	return prime, stats.UpperBound
+}
+
+// Algorithm - selects the internal sieve a PrimeNumberSieve uses, see WithAlgorithm
+type Algorithm int
+
+const (
+	// AlgorithmSegmented - the segmented sieve of Eratosthenes, sieving sqrt(n) sized segments at a time. The default,
+	// and the only algorithm that supports WithAdaptiveSegments and WithWorkers
+	AlgorithmSegmented Algorithm = iota
+	// AlgorithmBasic - the basic sieve of Eratosthenes, sieving all of 2 - n at once
+	AlgorithmBasic
+	// AlgorithmAtkin - the sieve of Atkin
+	AlgorithmAtkin
+	// AlgorithmWheel - the sieve of Eratosthenes on a 2-3-5 wheel, skipping multiples of 2, 3 and 5 entirely
+	AlgorithmWheel
+)
+
+// sieveFactories - creates the internal sieve for each algorithm, configured from the sieve's options
+var sieveFactories = map[Algorithm]func(s *PrimeNumberSieve) sieve{
+	AlgorithmSegmented: func(s *PrimeNumberSieve) sieve {
+		return &segmentedSieve{trace: s.trace, adaptive: s.adaptiveSegments, workers: s.workerCount()}
+	},
+	AlgorithmBasic: func(*PrimeNumberSieve) sieve { return &basicSieveOfEratosthenes{} },
+	AlgorithmAtkin: func(*PrimeNumberSieve) sieve { return &sieveOfAtkin{} },
+	AlgorithmWheel: func(*PrimeNumberSieve) sieve { return &wheelSieve{} },
 }
 
 // sieve - internal interface used to switch between sieve implementations
@@ -58,6 +185,41 @@ func (s *PrimeNumberSieve) NthPrime(nthPrime int64) int64 {
 // NOTE: This is not the same as the nth prime number.
 type sieve interface {
 	sieve(n int64) []int64
+	// name - identifies the algorithm, e.g. in traces
+	name() string
+}
+
+// contextSieve - implemented by sieves that can be abandoned part way through once a context is done
+type contextSieve interface {
+	sieveContext(ctx context.Context, n int64) ([]int64, error)
+}
+
+// primeExtender - implemented by sieves that can carry on from primes already found, rather than starting again from 2
+type primeExtender interface {
+	// extendContext - returns every prime up to n given primes, every prime up to bound < n, abandoning the sieve once
+	// ctx is done. primes is appended to, so the result may share its backing array
+	extendContext(ctx context.Context, primes []int64, bound, n int64) ([]int64, error)
+}
+
+// extendUntilDone - like sieveUntilDone, but carrying on from primes, every prime up to bound, when sieveFunc is a
+// primeExtender
+func extendUntilDone(ctx context.Context, sieveFunc sieve, primes []int64, bound, n int64) ([]int64, error) {
+	if pe, ok := sieveFunc.(primeExtender); ok {
+		return pe.extendContext(ctx, primes, bound, n)
+	}
+	return sieveUntilDone(ctx, sieveFunc, n)
+}
+
+// sieveUntilDone - runs sieveFunc up to n, abandoning it part way through if it's a contextSieve and ctx is done.
+// Other sieves always run to completion once started
+func sieveUntilDone(ctx context.Context, sieveFunc sieve, n int64) ([]int64, error) {
+	if cs, ok := sieveFunc.(contextSieve); ok {
+		return cs.sieveContext(ctx, n)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return sieveFunc.sieve(n), nil
 }
 
 // segmentedSieve - uses a segmented sieve to return a list of primes from 2 - n.
@@ -65,20 +227,39 @@ type sieve interface {
 // Following that it creates segments to loop through, marking off any additional composites in the process
 // finally, it adds the remaining primes before moving onto the next segment.
 type segmentedSieve struct {
+	// basicSieve - finds the base primes up to sqrt(n), the basic sieve of eratosthenes when nil
 	basicSieve sieve
+	trace      io.Writer
+	// adaptive - grow segments with ln(low) rather than using a fixed size, see adaptiveSegmentSize
+	adaptive bool
+	// workers - how many goroutines sieve segments at once, segments are sieved serially when 1 or less
+	workers int
 }
 
 // sieve - implementation of the segmented sieve
 func (s *segmentedSieve) sieve(n int64) []int64 {
-	if s.basicSieve == nil {
-		s.basicSieve = &basicSieveOfEratosthenes{}
+	res, _ := s.sieveContext(context.Background(), n)
+	return res
+}
+
+// sieveContext - implementation of the contextSieve interface, checking ctx before each segment
+func (s *segmentedSieve) sieveContext(ctx context.Context, n int64) ([]int64, error) {
+	// fall back to the default without writing it to the struct, so a shared segmentedSieve never races on the field
+	basicSieve := s.basicSieve
+	if basicSieve == nil {
+		basicSieve = &basicSieveOfEratosthenes{}
+	}
+
+	// bounds below 4 have no base prime to segment with, so the basic sieve handles them directly
+	if n < 4 {
+		return basicSieve.sieve(n), nil
 	}
 
 	// get segment size, use sqrt n as its consistent with what the basic sieve will use
 	segmentSize := int64(math.Sqrt(float64(n)))
 
 	// initialize primes up to sqrt(n) using the already created basic sieve of eratosthenes
-	primes := s.basicSieve.sieve(segmentSize)
+	primes := basicSieve.sieve(segmentSize)
 
 	// ensure the results contain all primes up to the square root of the upperbound found in the basic sieve
 	result := make([]int64, 0)
@@ -86,81 +267,307 @@ func (s *segmentedSieve) sieve(n int64) []int64 {
 		result = append(result, p)
 	}
 
-	// begin processing segments
-	for low := segmentSize; low <= n; low += segmentSize {
+	return s.segments(ctx, primes, segmentSize+1, segmentSize, n, result)
+}
+
+// extendContext - implementation of the primeExtender interface. The base primes up to sqrt(n) are already among
+// primes whenever bound is at least sqrt(n), as it is for a doubled bound, so only (bound, n] is sieved. Otherwise the
+// primes are sieved again from the start
+func (s *segmentedSieve) extendContext(ctx context.Context, primes []int64, bound, n int64) ([]int64, error) {
+	segmentSize := int64(math.Sqrt(float64(n)))
+	if bound < 4 || bound < segmentSize {
+		return s.sieveContext(ctx, n)
+	}
+
+	base := primes[:sort.Search(len(primes), func(i int) bool { return primes[i] > segmentSize })]
+	tracef(s.trace, "extending %d primes up to %d to %d", len(primes), bound, n)
+	return s.segments(ctx, base, bound+1, segmentSize, n, primes)
+}
+
+// segments - sieves [low, n] in segments with the base primes up to segmentSize, sqrt(n), appending the primes found to
+// result. Bounds that fit in an int32 use 32 bit index arithmetic, halving the memory needed for the base primes
+// and keeping more of them in cache while marking. Larger bounds transparently fall back to int64
+func (s *segmentedSieve) segments(ctx context.Context, primes []int64, low, segmentSize, n int64, result []int64) ([]int64, error) {
+	workers := s.workers
+	if procs := runtime.GOMAXPROCS(0); workers > procs {
+		// goroutines beyond the available processors only add overhead, with a single processor it's fully serial
+		workers = procs
+	}
+	if n <= int32SieveLimit {
+		return sieveSegmentsFrom(ctx, toWidth[int32](primes), int32(low), int32(segmentSize), int32(n), s.adaptive, workers, result, s.trace)
+	}
+	return sieveSegmentsFrom(ctx, primes, low, segmentSize, n, s.adaptive, workers, result, s.trace)
+}
+
+// name - implementation of the sieve interface
+func (s *segmentedSieve) name() string {
+	return "segmented"
+}
+
+// int32SieveLimit - the largest bound sieved using int32 arithmetic. The margin below math.MaxInt32 leaves room for
+// the final segment and the last multiple of a base prime to step past the bound without overflowing
+const int32SieveLimit = math.MaxInt32 - 1<<17
+
+// sieveInt - integer widths the segment loop can be run with
+type sieveInt interface {
+	int32 | int64
+}
+
+// toWidth - converts a list of primes to the integer width used by the segment loop
+func toWidth[T sieveInt](primes []int64) []T {
+	res := make([]T, len(primes))
+	for i, p := range primes {
+		res[i] = T(p)
+	}
+	return res
+}
+
+// sieveSegmentsFrom - processes the segments covering [low, n], appending the primes found to result. primes must hold
+// every prime up to segmentSize, the square root of n, and low must be above every one of them so they don't mark
+// themselves off. Sieving from segmentSize+1 finds every prime above the base primes, from higher up extends primes
+// already found below low. Segments are half-open so each one starts just after the previous one ends. When adaptive,
+// segments grow with ln(low) instead of all being segmentSize long. With more than one worker the segments are sieved
+// concurrently, see sieveSegmentsConcurrently. Returns ctx.Err() without the primes if ctx is done before every
+// segment has been sieved
+func sieveSegmentsFrom[T sieveInt](ctx context.Context, primes []T, low, segmentSize, n T, adaptive bool, workers int, result []int64, trace io.Writer) ([]int64, error) {
+	segments := segmentBoundsFrom(low, segmentSize, n, adaptive)
+	if workers > 1 && len(segments) > 1 {
+		tracef(trace, "sieving %d segments with up to %d at once", len(segments), workers)
+		return sieveSegmentsConcurrently(ctx, primes, segments, workers, result, trace)
+	}
+
+	tracef(trace, "sieving %d segments with up to 1 at once", len(segments))
+	for _, segment := range segments {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		tracef(trace, "sieving segment [%d, %d]", segment.low, segment.high)
+		result = sieveSegment(primes, segment.low, segment.high, result)
+	}
+	return result, nil
+}
+
+// bounds - the inclusive range [low, high] covered by a single segment
+type bounds[T sieveInt] struct {
+	low, high T
+}
+
+// segmentBoundsFrom - splits [from, n] into consecutive segments, see sieveSegmentsFrom
+func segmentBoundsFrom[T sieveInt](from, segmentSize, n T, adaptive bool) []bounds[T] {
+	segments := make([]bounds[T], 0)
+	for low := from; low <= n; {
 
-		high := low + segmentSize
+		size := segmentSize
+		if adaptive {
+			size = adaptiveSegmentSize(segmentSize, low)
+		}
+		high := low + size - 1
 
 		// high cannot be above the upperbound
 		if high > n {
 			high = n
 		}
 
-		// create a bool slice with enough capacity for the segment and mark them all as true
-		segment := make([]bool, high-low+1)
-		for i := range segment {
-			segment[i] = true
+		segments = append(segments, bounds[T]{low: low, high: high})
+		if high == n {
+			break
 		}
+		low = high + 1
+	}
 
-		for _, p := range primes {
-			start := (low + p - 1) / p * p // find the smallest multiple of p that is greater than or equal to low
-			// this is more performant than looping through and using % to find the start
+	return segments
+}
 
-			// ensure start is within the segment
-			if start < low {
-				start += p
+// sieveSegmentsConcurrently - sieves segments across a pool of workers goroutines, appending the primes found to result.
+// The base primes are only read, so every worker shares them. Each segment's primes go into its own bucket so they
+// can be appended to result in ascending order once every worker is done
+func sieveSegmentsConcurrently[T sieveInt](ctx context.Context, primes []T, segments []bounds[T], workers int, result []int64, trace io.Writer) ([]int64, error) {
+	buckets := make([][]int64, len(segments))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				buckets[i] = sieveSegment(primes, segments[i].low, segments[i].high, nil)
 			}
+		}()
+	}
 
-			// mark multiples of the prime as false in the segment
-			for i := start; i <= high; i += p {
-				segment[i-low] = false
-			}
+	// segments are handed out, and traced, in order from this goroutine so the trace stays readable.
+	// Once ctx is done no more are handed out, and the workers finish only the segments they already have
+dispatch:
+	for i, segment := range segments {
+		tracef(trace, "sieving segment [%d, %d]", segment.low, segment.high)
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
 		}
+	}
+	close(jobs)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-		// Collect primes from the segment
-		for i := low; i <= high; i++ {
-			if segment[i-low] {
-				result = append(result, i)
+	total := len(result)
+	for _, bucket := range buckets {
+		total += len(bucket)
+	}
+	merged := make([]int64, 0, total)
+	merged = append(merged, result...)
+	for _, bucket := range buckets {
+		merged = append(merged, bucket...)
+	}
+	return merged, nil
+}
+
+// adaptiveSegmentSize - scales segmentSize by ln(low)/ln(segmentSize). Primes thin out at a rate of 1/ln(x), so this
+// keeps the expected number of primes per segment roughly constant from the first segment (at sqrt(n)) to the last,
+// where segments are about twice as long
+func adaptiveSegmentSize[T sieveInt](segmentSize, low T) T {
+	return T(float64(segmentSize) * math.Log(float64(low)) / math.Log(float64(segmentSize)))
+}
+
+// forEachSegment - marks [lo, hi] in segments of at most segmentSize, passing each one to fn until it returns false.
+// primes must hold every prime up to sqrt(hi), and lo must be above all of them so they don't mark themselves off
+func forEachSegment(primes []int64, lo, hi, segmentSize int64, fn func(low int64, segment []bool) bool) {
+	for low := lo; low <= hi; {
+		// stop at hi without stepping past it, hi may be close to math.MaxInt64
+		high := hi
+		if hi-low >= segmentSize {
+			high = low + segmentSize - 1
+		}
+		if !fn(low, markSegment(primes, low, high)) || high == hi {
+			return
+		}
+		low = high + 1
+	}
+}
+
+// sieveSegment - marks off the multiples of primes within [low, high] and appends the remaining primes to result
+func sieveSegment[T sieveInt](primes []T, low, high T, result []int64) []int64 {
+	return collectSegment(markSegment(primes, low, high), low, result)
+}
+
+// markSegment - returns a bool slice for [low, high] where segment[i] is true if low+i is not a multiple of any prime
+func markSegment[T sieveInt](primes []T, low, high T) []bool {
+	// create a bool slice with enough capacity for the segment and mark them all as true
+	segment := make([]bool, high-low+1)
+	for i := range segment {
+		segment[i] = true
+	}
+
+	for _, p := range primes {
+		// the smallest multiple of p that is greater than or equal to low, skipping p if it's past high, where
+		// rounding low up could step past an int64
+		offset := (p - low%p) % p
+		if offset > high-low {
+			continue
+		}
+		start := low + offset
+
+		// mark multiples of the prime as false in the segment, stopping before i would step past an int64
+		for i := start; ; i += p {
+			segment[i-low] = false
+			if high-i < p {
+				break
 			}
 		}
 	}
 
+	return segment
+}
+
+// collectSegment - appends every number still marked as prime in a segment starting at low to result
+func collectSegment[T sieveInt](segment []bool, low T, result []int64) []int64 {
+	for i, isPrime := range segment {
+		if isPrime {
+			result = append(result, int64(low)+int64(i))
+		}
+	}
 	return result
 }
 
 // basicSieveOfEratosthenes - used to switch between sieve algorithms in the PrimeNumberSieve
 type basicSieveOfEratosthenes struct{}
 
+// name - implementation of the sieve interface
+func (b *basicSieveOfEratosthenes) name() string {
+	return "basic"
+}
+
 // basicSieveOfEratosthenes - uses a basic sieve of Erastothenes to return a list of primes from 2 - n
 func (b *basicSieveOfEratosthenes) sieve(n int64) []int64 {
+	// tiny bounds are served straight from the small primes table without sieving
+	if n <= smallPrimesBound {
+		return smallPrimesUpTo(n)
+	}
+	return eratosthenes(n)
+}
+
+// the first 100 primes, up to and including smallPrimesBound, computed once at init and shared across the package.
+// The table is authoritative up to smallPrimesBound, so IsPrime and the basic sieve answer any n at or below it from the
+// table alone without sieving or Miller-Rabin
+const smallPrimesBound = 541
+
+var smallPrimes = eratosthenes(smallPrimesBound)
+
+// smallPrimesUpTo - returns a copy of the small primes <= n, for n <= smallPrimesBound
+func smallPrimesUpTo(n int64) []int64 {
+	count := sort.Search(len(smallPrimes), func(i int) bool { return smallPrimes[i] > n })
+	return append(make([]int64, 0, count), smallPrimes[:count]...)
+}
 
-	// create a list of bools from 0 to upperbounds (n)
-	isPrime := make([]bool, n+1)
-	for i := 0; int64(i) <= n; i++ {
-		isPrime[i] = true
+// eratosthenes - the sieve of Erastothenes itself, returning a list of primes from 2 - n
+func eratosthenes(n int64) []int64 {
+	if n < 2 {
+		return make([]int64, 0)
 	}
 
-	// 0 and 1 are not prime numbers by definition so mark them false
-	isPrime[0] = false
-	isPrime[1] = false
+	// create a set of bits from 0 to upperbounds (n), tracking composites so the zero value (unset) already means
+	// prime and no initialization pass over the whole set is needed. Packing the bits keeps it to n/8 bytes
+	isComposite := newBitset(n + 1)
 
 	// loop through all primes from 2 to the square root of n (simple optimization: no need to check above sqrt(n) as a previous prime would already marked these)
-	// if i is marked as a prime, mark all multiples of i as composites (false)
-	for i := 2; int64(i)*int64(i) <= n; i++ {
-		if isPrime[i] {
-			for j := i * i; int64(j) <= n; j += i {
-				isPrime[j] = false
+	// if i is still marked as a prime, mark all multiples of i as composites (set)
+	// indices are int64 throughout, on 32 bit platforms an int would overflow when stepping j past a large n
+	for i := int64(2); i*i <= n; i++ {
+		if !isComposite.get(i) {
+			for j := i * i; j <= n; j += i {
+				isComposite.set(j)
 			}
 		}
 	}
 
-	// append all primes from 2 to n to results and return
+	// append all primes from 2 to n to results and return, skipping 0 and 1 which are not prime numbers by definition
 	res := make([]int64, 0)
-	for i, potentialPrime := range isPrime {
-		if potentialPrime {
-			res = append(res, int64(i))
+	for i := int64(2); i <= n; i++ {
+		if !isComposite.get(i) {
+			res = append(res, i)
 		}
 	}
 
 	return res
 }
+
+// bitset - a fixed size set of bits packed 64 to a word, an eighth of the memory of the equivalent []bool
+type bitset []uint64
+
+// newBitset - creates a bitset holding bits 0 to size-1, all unset
+func newBitset(size int64) bitset {
+	return make(bitset, (size+63)/64)
+}
+
+// get - reports whether bit i is set
+func (b bitset) get(i int64) bool {
+	return b[i>>6]&(1<<(uint64(i)&63)) != 0
+}
+
+// set - sets bit i
+func (b bitset) set(i int64) {
+	b[i>>6] |= 1 << (uint64(i) & 63)
+}
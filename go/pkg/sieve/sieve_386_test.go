@@ -0,0 +1,17 @@
+package sieve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNthPrime32Bit(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	assert.Equal(t, int64(256<<20), sieve.memoryLimit)
+	assert.Equal(t, int64(15485867), sieve.NthPrime(1000000))
+
+	// the basic sieve's indices stay in int64 even though int is 32 bits
+	primes := (&basicSieveOfEratosthenes{}).sieve(1 << 24)
+	assert.Equal(t, int64(16777213), primes[len(primes)-1])
+}
@@ -1,8 +1,14 @@
 package sieve
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"runtime"
+	"sync"
 	"testing"
 	"time"
 
@@ -10,7 +16,7 @@ import (
 )
 
 func TestNthPrime(t *testing.T) {
-	sieve := NewPrimeNumberSieve()
+	sieve := NewSieve()
 
 	startTime := time.Now()
 	fmt.Println("starting test")
@@ -28,12 +34,640 @@ func TestNthPrime(t *testing.T) {
 	fmt.Println("ending test, test took", endTime.Sub(startTime))
 }
 
+func TestNthPrimeAlgorithms(t *testing.T) {
+	expected := map[int64]int64{-1: 0, 0: 2, 19: 71, 99: 541, 500: 3581, 986: 7793, 2000: 17393, 1000000: 15485867}
+
+	for name, algorithm := range map[string]Algorithm{
+		"segmented": AlgorithmSegmented,
+		"basic":     AlgorithmBasic,
+		"atkin":     AlgorithmAtkin,
+		"wheel":     AlgorithmWheel,
+	} {
+		sieve := NewPrimeNumberSieve(WithAlgorithm(algorithm))
+		assert.Equal(t, name, sieve.newSieve().name())
+		for n, want := range expected {
+			assert.Equal(t, want, sieve.NthPrime(n), "%s n=%d", name, n)
+		}
+	}
+}
+
 func FuzzNthPrime(f *testing.F) {
-	sieve := NewPrimeNumberSieve()
+	sieve := NewSieve()
 
 	f.Fuzz(func(t *testing.T, n int64) {
+		// negative indices have no prime, and return 0
+		if n < 0 {
+			if p := sieve.NthPrime(n); p != 0 {
+				t.Errorf("the sieve produced %d for the negative index %d", p, n)
+			}
+			return
+		}
+		n %= fuzzMaxIndex
+
 		if !big.NewInt(sieve.NthPrime(n)).ProbablyPrime(0) {
 			t.Errorf("the sieve produced a non-prime number at index %d", n)
 		}
 	})
 }
+
+// fuzzMaxIndex - bounds the indices the fuzz targets check, keeping each input fast
+const fuzzMaxIndex = 1000000
+
+func FuzzMonotonic(f *testing.F) {
+	// around the tiny bound estimate, the end of the small primes table and the first few segment boundaries
+	for _, n := range []int64{0, 1, 3, 4, 5, 6, 98, 99, 100, 168, 1228, 1229, 9591, 78497, fuzzMaxIndex - 1} {
+		f.Add(n)
+	}
+
+	sieve := NewPrimeNumberSieve(WithWorkers(4))
+	f.Fuzz(func(t *testing.T, n int64) {
+		if n < 0 {
+			n = -(n + 1)
+		}
+		n %= fuzzMaxIndex
+
+		if p, next := sieve.NthPrime(n), sieve.NthPrime(n+1); p >= next {
+			t.Errorf("the sieve is out of order at index %d: %d is followed by %d", n, p, next)
+		}
+	})
+}
+
+// BenchmarkRepeatedNthPrime - calls NthPrime for increasing indices, comparing a single shared sieve
+// (which is able to reuse previously computed primes) against a fresh sieve per call
+func BenchmarkRepeatedNthPrime(b *testing.B) {
+	const maxIndex = 10000
+
+	b.Run("cached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			sieve := NewPrimeNumberSieve()
+			for n := int64(0); n < maxIndex; n++ {
+				sieve.NthPrime(n)
+			}
+		}
+	})
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for n := int64(0); n < maxIndex; n++ {
+				NewPrimeNumberSieve().NthPrime(n)
+			}
+		}
+	})
+}
+
+func TestNewSieve(t *testing.T) {
+	sieve := NewSieve()
+	assert.Equal(t, int64(541), sieve.NthPrime(99))
+
+	// the default configuration, with the full API still reachable through the concrete type
+	concrete, ok := sieve.(*PrimeNumberSieve)
+	assert.True(t, ok)
+	assert.Equal(t, "segmented", concrete.newSieve().name())
+	assert.Equal(t, defaultMemoryLimit, concrete.memoryLimit)
+	assert.True(t, concrete.IsPrime(541))
+}
+
+func TestPrimeAt(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	for n, want := range map[int64]int64{0: 2, 1: 3, 19: 71, 99: 541, 1000000: 15485867} {
+		prime, err := sieve.PrimeAt(n)
+		assert.NoError(t, err)
+		assert.Equal(t, want, prime)
+	}
+
+	_, err := sieve.PrimeAt(-1)
+	assert.True(t, errors.Is(err, ErrNegativeIndex))
+
+	_, err = sieve.PrimeAt(maxPrimeIndex + 1)
+	assert.True(t, errors.Is(err, ErrIndexTooLarge))
+
+	// NthPrime swallows the errors
+	assert.Equal(t, int64(0), sieve.NthPrime(-1))
+	assert.Equal(t, int64(0), sieve.NthPrime(maxPrimeIndex+1))
+}
+
+func TestNthPrimeE(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	prime, err := sieve.NthPrimeE(-5)
+	assert.True(t, errors.Is(err, ErrNegativeIndex))
+	assert.Equal(t, int64(0), prime)
+
+	prime, err = sieve.NthPrimeE(maxPrimeIndex + 1)
+	assert.True(t, errors.Is(err, ErrIndexTooLarge))
+	assert.Equal(t, int64(0), prime)
+
+	// the 0th prime is a real answer, with no error
+	prime, err = sieve.NthPrimeE(0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), prime)
+
+	// as an alias it answers exactly as PrimeAt does
+	for _, n := range []int64{-1, 0, 99, 100000, maxPrimeIndex + 1} {
+		want, wantErr := sieve.PrimeAt(n)
+		prime, err = sieve.NthPrimeE(n)
+		assert.Equal(t, want, prime, "n=%d", n)
+		assert.Equal(t, wantErr, err, "n=%d", n)
+	}
+}
+
+func TestNthPrimeCtx(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	prime, err := sieve.NthPrimeCtx(context.Background(), 99)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(541), prime)
+
+	_, err = sieve.NthPrimeCtx(context.Background(), -1)
+	assert.True(t, errors.Is(err, ErrNegativeIndex))
+
+	// an already cancelled context never starts sieving, while the cache still answers what it covers
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = sieve.NthPrimeCtx(ctx, 1000000)
+	assert.True(t, errors.Is(err, context.Canceled))
+	prime, err = sieve.NthPrimeCtx(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(31), prime)
+}
+
+func TestNthPrimeCtxCancelledMidComputation(t *testing.T) {
+	for name, opts := range map[string][]Option{
+		"serial":     {WithWorkers(1)},
+		"concurrent": {WithWorkers(4)},
+		"streaming":  {WithMemoryLimit(1 << 20)},
+	} {
+		sieve := NewPrimeNumberSieve(opts...)
+
+		// the full computation takes several seconds
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		start := time.Now()
+		prime, err := sieve.NthPrimeCtx(ctx, 100000000)
+		cancel()
+
+		assert.True(t, errors.Is(err, context.DeadlineExceeded), name)
+		assert.Equal(t, int64(0), prime, name)
+		assert.Less(t, time.Since(start), time.Second, name)
+
+		// and nothing partial was cached
+		assert.False(t, sieve.CacheCovers(1))
+	}
+}
+
+func TestNthPrimeCtxWaitingOnCache(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	sieve.Prewarm(100)
+
+	// another query holding the cache, as if sieving for a long time
+	sieve.cache.mu.Lock()
+
+	// a done context returns straight away rather than waiting, even for an index the cache would serve once free
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	prime, err := sieve.NthPrimeCtx(ctx, 10)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, int64(0), prime)
+
+	// and one that's done while waiting stops waiting
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	start := time.Now()
+	_, err = sieve.NthPrimeCtx(ctx, 10)
+	cancel()
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// once the cache is released queries carry on as normal
+	sieve.cache.mu.Unlock()
+	prime, err = sieve.NthPrimeCtx(context.Background(), 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(31), prime)
+}
+
+func TestNthPrimeAndBound(t *testing.T) {
+	for _, n := range []int64{0, 4, 5, 10, 99, 1000, 100000} {
+		prime, bound := NewPrimeNumberSieve().NthPrimeAndBound(n)
+		assert.Equal(t, NewPrimeNumberSieve().NthPrime(n), prime, "n=%d", n)
+		assert.GreaterOrEqual(t, bound, prime, "n=%d", n)
+		assert.Equal(t, EstimateUpperBound(n), bound, "n=%d", n)
+	}
+
+	// a bound that had to be doubled is reported as the doubled bound
+	sieve := NewPrimeNumberSieve()
+	sieve.cache.mu.Lock()
+	sieve.nthPrimeFromLocked(context.Background(), 10, 20, SieveStats{})
+	sieve.cache.mu.Unlock()
+
+	prime, bound := sieve.NthPrimeAndBound(10)
+	assert.Equal(t, int64(31), prime)
+	assert.Equal(t, int64(40), bound)
+
+	prime, bound = sieve.NthPrimeAndBound(-1)
+	assert.Equal(t, int64(0), prime)
+	assert.Equal(t, int64(0), bound)
+}
+
+func TestInt32SegmentsMatchInt64AtCrossover(t *testing.T) {
+	segmentSize := isqrt(int32SieveLimit)
+	primes := (&basicSieveOfEratosthenes{}).sieve(segmentSize)
+
+	// the final segment below the crossover is the one most likely to overflow 32 bit arithmetic
+	low, high := int64(int32SieveLimit)-segmentSize, int64(int32SieveLimit)
+	want := sieveSegment(primes, low, high, nil)
+	got := sieveSegment(toWidth[int32](primes), int32(low), int32(high), nil)
+
+	assert.NotEmpty(t, want)
+	assert.Equal(t, want, got)
+
+	// and both widths agree across a full sieve
+	n := int64(1000000)
+	primes = (&basicSieveOfEratosthenes{}).sieve(isqrt(n))
+	all := backgroundSegments(primes, isqrt(n), n, false, 1, nil, nil)
+	assert.Equal(t, all, backgroundSegments(toWidth[int32](primes), int32(isqrt(n)), int32(n), false, 1, nil, nil))
+	assert.True(t, withinChebyshevBounds(n, int64(len(all))))
+}
+
+func BenchmarkSegmentWidth(b *testing.B) {
+	n := int64(1000000)
+	primes := (&basicSieveOfEratosthenes{}).sieve(isqrt(n))
+
+	b.Run("int32", func(b *testing.B) {
+		primes32 := toWidth[int32](primes)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			backgroundSegments(primes32, int32(isqrt(n)), int32(n), false, 1, nil, nil)
+		}
+	})
+
+	b.Run("int64", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			backgroundSegments(primes, isqrt(n), n, false, 1, nil, nil)
+		}
+	})
+}
+
+// BenchmarkSegmentPhases - isolates the cost of collecting primes from a segment from the cost of marking it
+func BenchmarkSegmentPhases(b *testing.B) {
+	n := int64(10000000)
+	segmentSize := isqrt(n)
+	primes := (&basicSieveOfEratosthenes{}).sieve(segmentSize)
+
+	b.Run("mark-only", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for low := segmentSize; low <= n; low += segmentSize {
+				markSegment(primes, low, low+segmentSize)
+			}
+		}
+	})
+
+	b.Run("mark-and-collect", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			result := make([]int64, 0)
+			for low := segmentSize; low <= n; low += segmentSize {
+				result = collectSegment(markSegment(primes, low, low+segmentSize), low, result)
+			}
+		}
+	})
+}
+
+func TestSieveSmallBounds(t *testing.T) {
+	expected := map[int64][]int64{-1: {}, 0: {}, 1: {}, 2: {2}, 3: {2, 3}, 4: {2, 3}, 5: {2, 3, 5}}
+	for n, want := range expected {
+		assert.Equal(t, want, (&basicSieveOfEratosthenes{}).sieve(n), "basic sieve n=%d", n)
+		assert.Equal(t, want, (&segmentedSieve{}).sieve(n), "segmented sieve n=%d", n)
+	}
+}
+
+func TestAdaptiveSegmentsMatchFixed(t *testing.T) {
+	for _, n := range []int64{4, 5, 20, 1000, 99991, 1000000} {
+		fixed := (&segmentedSieve{}).sieve(n)
+		adaptive := (&segmentedSieve{adaptive: true}).sieve(n)
+		assert.Equal(t, fixed, adaptive, "n=%d", n)
+		assert.True(t, withinChebyshevBounds(n, int64(len(adaptive))), "n=%d", n)
+	}
+
+	sieve := NewPrimeNumberSieve(WithAdaptiveSegments(true))
+	assert.Equal(t, int64(15485867), sieve.NthPrime(1000000))
+}
+
+func TestAdaptiveSegmentSize(t *testing.T) {
+	// segments start at the fixed size and double by the time they reach n
+	assert.Equal(t, int64(1000), adaptiveSegmentSize(int64(1000), int64(1000)))
+	assert.Equal(t, int64(2000), adaptiveSegmentSize(int64(1000), int64(1000000)))
+}
+
+func TestSegmentBoundsTile(t *testing.T) {
+	// segments cover [from, n] exactly: each starts right after the last one ends, none is empty, none passes n
+	for _, adaptive := range []bool{false, true} {
+		for _, c := range []struct{ from, segmentSize, n int64 }{
+			{3, 2, 4}, {5, 4, 20}, {11, 10, 100}, {11, 10, 101}, {1001, 1000, 1000000}, {5000, 1000, 1000000},
+		} {
+			segments := segmentBoundsFrom(c.from, c.segmentSize, c.n, adaptive)
+			next := c.from
+			for _, segment := range segments {
+				assert.Equal(t, next, segment.low, "%+v adaptive=%t", c, adaptive)
+				assert.LessOrEqual(t, segment.low, segment.high, "%+v adaptive=%t", c, adaptive)
+				next = segment.high + 1
+			}
+			assert.Equal(t, c.n+1, next, "%+v adaptive=%t", c, adaptive)
+		}
+	}
+
+	// int32 bounds can end right at the sieve limit without overflowing
+	segments := segmentBoundsFrom(int32(46341), int32(46340), int32(int32SieveLimit), false)
+	assert.Equal(t, int32(46341), segments[0].low)
+	assert.Equal(t, int32(int32SieveLimit), segments[len(segments)-1].high)
+	assert.Empty(t, segmentBoundsFrom(int64(11), int64(10), int64(10), false))
+}
+
+func BenchmarkAdaptiveSegments(b *testing.B) {
+	n := int64(100000000)
+
+	b.Run("fixed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			(&segmentedSieve{}).sieve(n)
+		}
+	})
+
+	b.Run("adaptive", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			(&segmentedSieve{adaptive: true}).sieve(n)
+		}
+	})
+}
+
+// TestSegmentedSieveConcurrentUse - run with -race to confirm a shared segmentedSieve doesn't race on its base sieve
+func TestSegmentedSieveConcurrentUse(t *testing.T) {
+	shared := &segmentedSieve{}
+	want := (&basicSieveOfEratosthenes{}).sieve(100000)
+
+	var wg sync.WaitGroup
+	results := make([][]int64, 8)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = shared.sieve(100000)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, res := range results {
+		assert.Equal(t, want, res)
+	}
+	assert.Nil(t, shared.basicSieve)
+}
+
+func TestExtendMatchesFullSieve(t *testing.T) {
+	s := &segmentedSieve{}
+	for _, c := range []struct{ bound, n int64 }{{20, 40}, {36, 41}, {100, 101}, {1000, 2000}, {99991, 200000}, {100000, 1000000}} {
+		ext, err := s.extendContext(context.Background(), s.sieve(c.bound), c.bound, c.n)
+		assert.NoError(t, err)
+		assert.Equal(t, s.sieve(c.n), ext, "bound=%d n=%d", c.bound, c.n)
+	}
+
+	// a bound below sqrt(n) doesn't hold the base primes, so sieving starts again from 2
+	ext, _ := s.extendContext(context.Background(), s.sieve(10), 10, 1000)
+	assert.Equal(t, s.sieve(1000), ext)
+	ext, _ = s.extendContext(context.Background(), nil, 0, 100)
+	assert.Equal(t, s.sieve(100), ext)
+}
+
+func TestSegmentedSievePerfectSquares(t *testing.T) {
+	// sqrt(n) is exact for these, so the segment size lands exactly on a base prime (or its square) and the
+	// last segment ends exactly on n
+	for _, n := range []int64{4, 9, 25, 49, 100, 121, 10000, 994009} {
+		want := (&basicSieveOfEratosthenes{}).sieve(n)
+		assert.Equal(t, want, (&segmentedSieve{}).sieve(n), "n=%d", n)
+		assert.Equal(t, want, (&segmentedSieve{adaptive: true}).sieve(n), "adaptive n=%d", n)
+
+		// and either side of the square
+		assert.Equal(t, (&basicSieveOfEratosthenes{}).sieve(n-1), (&segmentedSieve{}).sieve(n-1), "n=%d", n-1)
+		assert.Equal(t, (&basicSieveOfEratosthenes{}).sieve(n+1), (&segmentedSieve{}).sieve(n+1), "n=%d", n+1)
+	}
+
+	// the int64 segment loop handles the same boundaries as the int32 one
+	n := int64(10000)
+	primes := (&basicSieveOfEratosthenes{}).sieve(isqrt(n))
+	assert.Equal(t, (&basicSieveOfEratosthenes{}).sieve(n), backgroundSegments(primes, isqrt(n), n, false, 1, append([]int64{}, primes...), nil))
+}
+
+// backgroundSegments - runs sieveSegmentsFrom over (segmentSize, n] to completion with a context that is never done
+func backgroundSegments[T sieveInt](primes []T, segmentSize, n T, adaptive bool, workers int, result []int64, trace io.Writer) []int64 {
+	res, _ := sieveSegmentsFrom(context.Background(), primes, segmentSize+1, segmentSize, n, adaptive, workers, result, trace)
+	return res
+}
+
+// trueInitializedSieve - the basic sieve as it was before tracking composites, which first set every entry to true.
+// Kept as a reference for the current implementation's output and performance
+func trueInitializedSieve(n int64) []int64 {
+	isPrime := make([]bool, n+1)
+	for i := 0; int64(i) <= n; i++ {
+		isPrime[i] = true
+	}
+	isPrime[0] = false
+	isPrime[1] = false
+
+	for i := 2; int64(i)*int64(i) <= n; i++ {
+		if isPrime[i] {
+			for j := i * i; int64(j) <= n; j += i {
+				isPrime[j] = false
+			}
+		}
+	}
+
+	res := make([]int64, 0)
+	for i, potentialPrime := range isPrime {
+		if potentialPrime {
+			res = append(res, int64(i))
+		}
+	}
+	return res
+}
+
+// byteSliceSieve - the basic sieve as it was before bit-packing, tracking composites in a []bool of one byte per
+// number. Kept as a reference for the bit-packed implementation's output and memory use
+func byteSliceSieve(n int64) []int64 {
+	isComposite := make([]bool, n+1)
+	for i := int64(2); i*i <= n; i++ {
+		if !isComposite[i] {
+			for j := i * i; j <= n; j += i {
+				isComposite[j] = true
+			}
+		}
+	}
+
+	res := make([]int64, 0)
+	for i := int64(2); i <= n; i++ {
+		if !isComposite[i] {
+			res = append(res, i)
+		}
+	}
+	return res
+}
+
+func TestBasicSieveMatchesByteSlice(t *testing.T) {
+	// bounds either side of each 64 bit word boundary, as well as the large case
+	for n := int64(542); n <= 800; n++ {
+		if !assert.Equal(t, byteSliceSieve(n), eratosthenes(n), "n=%d", n) {
+			break
+		}
+	}
+	assert.Equal(t, byteSliceSieve(1000000), (&basicSieveOfEratosthenes{}).sieve(1000000))
+}
+
+func TestBitset(t *testing.T) {
+	bits := newBitset(130)
+	assert.Len(t, bits, 3)
+
+	for _, i := range []int64{0, 63, 64, 129} {
+		assert.False(t, bits.get(i))
+		bits.set(i)
+		assert.True(t, bits.get(i))
+	}
+	assert.False(t, bits.get(1))
+	assert.False(t, bits.get(65))
+	assert.Equal(t, uint64(1|1<<63), bits[0])
+}
+
+func TestBasicSieveMatchesTrueInitialized(t *testing.T) {
+	for _, n := range []int64{2, 3, 4, 10, 97, 100, 7919, 1000000} {
+		primes := (&basicSieveOfEratosthenes{}).sieve(n)
+		assert.Equal(t, trueInitializedSieve(n), primes, "n=%d", n)
+		assert.True(t, withinChebyshevBounds(n, int64(len(primes))), "n=%d", n)
+	}
+}
+
+func BenchmarkBasicSieveInitialization(b *testing.B) {
+	n := int64(10000000)
+
+	b.Run("true-initialized", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			trueInitializedSieve(n)
+		}
+	})
+
+	b.Run("composite-tracking", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			byteSliceSieve(n)
+		}
+	})
+
+	b.Run("bit-packed", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			(&basicSieveOfEratosthenes{}).sieve(n)
+		}
+	})
+}
+
+func TestSmallPrimes(t *testing.T) {
+	assert.Len(t, smallPrimes, 100)
+	assert.Equal(t, int64(smallPrimesBound), smallPrimes[99])
+	assert.Equal(t, trueInitializedSieve(smallPrimesBound), smallPrimes)
+
+	for n := int64(2); n <= smallPrimesBound+1; n++ {
+		assert.Equal(t, trueInitializedSieve(n), (&basicSieveOfEratosthenes{}).sieve(n), "n=%d", n)
+	}
+
+	// the tiny bound path only allocates the returned copy, never a sieve
+	allocs := testing.AllocsPerRun(100, func() {
+		(&basicSieveOfEratosthenes{}).sieve(500)
+	})
+	assert.Equal(t, float64(1), allocs)
+
+	// and callers can't modify the shared table through it
+	primes := (&basicSieveOfEratosthenes{}).sieve(10)
+	primes[0] = 4
+	assert.Equal(t, int64(2), smallPrimes[0])
+}
+
+func TestConcurrentSegmentsMatchSerial(t *testing.T) {
+	for _, n := range []int64{4, 5, 100, 99991, 1000000} {
+		primes := (&basicSieveOfEratosthenes{}).sieve(isqrt(n))
+		serial := backgroundSegments(primes, isqrt(n), n, false, 1, append([]int64{}, primes...), nil)
+
+		// called directly, so the workers aren't capped at GOMAXPROCS
+		for _, workers := range []int{2, 3, 8} {
+			concurrent := backgroundSegments(primes, isqrt(n), n, false, workers, append([]int64{}, primes...), nil)
+			assert.Equal(t, serial, concurrent, "n=%d workers=%d", n, workers)
+
+			adaptive := backgroundSegments(primes, isqrt(n), n, true, workers, append([]int64{}, primes...), nil)
+			assert.Equal(t, serial, adaptive, "adaptive n=%d workers=%d", n, workers)
+		}
+	}
+
+	sieve := NewPrimeNumberSieve(WithWorkers(4))
+	assert.Equal(t, int64(15485867), sieve.NthPrime(1000000))
+}
+
+func TestSegmentedSieveSingleProcessor(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(1))
+
+	// the worker count the sieve settles on is traced, with a single processor the segments are sieved serially
+	var trace bytes.Buffer
+	primes := (&segmentedSieve{workers: 8, trace: &trace}).sieve(1000000)
+	assert.Equal(t, (&basicSieveOfEratosthenes{}).sieve(1000000), primes)
+	assert.Contains(t, trace.String(), "sieving 999 segments with up to 1 at once")
+
+	// while more processors allow as many workers, up to the number configured
+	runtime.GOMAXPROCS(2)
+	trace.Reset()
+	(&segmentedSieve{workers: 8, trace: &trace}).sieve(1000000)
+	assert.Contains(t, trace.String(), "sieving 999 segments with up to 2 at once")
+}
+
+func BenchmarkConcurrentSegments(b *testing.B) {
+	for name, workers := range map[string]int{"serial": 1, "parallel": runtime.NumCPU()} {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				NewPrimeNumberSieve(WithWorkers(workers)).NthPrime(10000000)
+			}
+		})
+	}
+}
+
+func TestSegmentedSieveStrictlyIncreasing(t *testing.T) {
+	n := int64(1000000)
+	for name, s := range map[string]*segmentedSieve{
+		"fixed":      {},
+		"adaptive":   {adaptive: true},
+		"concurrent": {workers: 4},
+	} {
+		result := s.sieve(n)
+		assert.Len(t, result, 78498, name)
+		for i := 0; i+1 < len(result); i++ {
+			if result[i] >= result[i+1] {
+				assert.Fail(t, "primes out of order", "%s: result[%d] = %d, result[%d] = %d", name, i, result[i], i+1, result[i+1])
+				break
+			}
+		}
+	}
+}
+
+// BenchmarkParallelScaling - sieves a fixed bound with an increasing number of workers, reporting the primes found per
+// second. Workers are capped at GOMAXPROCS, so run with e.g. -cpu 8 to see all of them
+func BenchmarkParallelScaling(b *testing.B) {
+	n := int64(50000000)
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers-%d", workers), func(b *testing.B) {
+			s := &segmentedSieve{workers: workers}
+			var found int
+			start := time.Now()
+			for i := 0; i < b.N; i++ {
+				found = len(s.sieve(n))
+			}
+			b.ReportMetric(float64(found)*float64(b.N)/time.Since(start).Seconds(), "primes/s")
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package sieve
+
+import (
+	"container/heap"
+	"context"
+)
+
+// MergePrimeStreams - k-way merges streams of primes, each already in ascending order (such as the output of separate
+// shards), into a single ascending stream with duplicates removed. Only the head of each stream is held at a time.
+// The returned channel is closed once every input stream is closed, and must be drained to avoid leaking the merge
+func MergePrimeStreams(streams ...<-chan int64) <-chan int64 {
+	out := make(chan int64)
+
+	go func() {
+		defer close(out)
+
+		// seed the heap with the first value of every stream that has one
+		h := make(streamHeap, 0, len(streams))
+		for _, stream := range streams {
+			if v, ok := <-stream; ok {
+				h = append(h, streamHead{value: v, stream: stream})
+			}
+		}
+		heap.Init(&h)
+
+		emitted := false
+		var last int64
+		for h.Len() > 0 {
+			head := &h[0]
+			if !emitted || head.value != last {
+				out <- head.value
+				last, emitted = head.value, true
+			}
+
+			// replace the head with the next value from the same stream, dropping the stream once it's closed
+			if v, ok := <-head.stream; ok {
+				head.value = v
+				heap.Fix(&h, 0)
+			} else {
+				heap.Pop(&h)
+			}
+		}
+	}()
+
+	return out
+}
+
+// streamHead - the smallest value not yet merged from a stream
+type streamHead struct {
+	value  int64
+	stream <-chan int64
+}
+
+// streamHeap - a min heap of stream heads, ordered by value
+type streamHeap []streamHead
+
+func (h streamHeap) Len() int            { return len(h) }
+func (h streamHeap) Less(i, j int) bool  { return h[i].value < h[j].value }
+func (h streamHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *streamHeap) Push(x interface{}) { *h = append(*h, x.(streamHead)) }
+func (h *streamHeap) Pop() interface{} {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
+
+// FanOutPolicy - what PrimesFanOut does when a consumer's buffer is full
+type FanOutPolicy int
+
+const (
+	// FanOutBlock - wait for the consumer to make room, so every consumer receives every prime but the slowest
+	// consumer sets the pace for all of them. The default
+	FanOutBlock FanOutPolicy = iota
+	// FanOutDrop - skip the prime for that consumer only, so a slow consumer can't hold up the others but may see gaps
+	FanOutDrop
+)
+
+// defaultFanOutBuffer - how many primes PrimesFanOut buffers per consumer unless configured with WithFanOut
+const defaultFanOutBuffer = 64
+
+// fanOutWindow - how many numbers PrimesFanOut sieves at a time
+const fanOutWindow = 1 << 16
+
+// PrimesFanOut - broadcasts the primes in ascending order, from 2 and without end, to consumers channels that each
+// receive every prime (subject to the WithFanOut policy), so independent pipelines can process the same stream.
+// Every channel is closed once ctx is done
+func (s *PrimeNumberSieve) PrimesFanOut(ctx context.Context, consumers int) []<-chan int64 {
+	if consumers < 1 {
+		return make([]<-chan int64, 0)
+	}
+
+	buffer := s.fanOutBuffer
+	if buffer < 1 {
+		buffer = defaultFanOutBuffer
+	}
+
+	outs := make([]chan int64, consumers)
+	res := make([]<-chan int64, consumers)
+	for i := range outs {
+		outs[i] = make(chan int64, buffer)
+		res[i] = outs[i]
+	}
+
+	go func() {
+		defer func() {
+			for _, out := range outs {
+				close(out)
+			}
+		}()
+
+		for lo := int64(2); lo > 0; lo += fanOutWindow {
+			for _, p := range s.PrimesInRange(lo, lo+fanOutWindow-1) {
+				for _, out := range outs {
+					if !s.sendFanOut(ctx, out, p) {
+						return
+					}
+				}
+			}
+		}
+	}()
+
+	return res
+}
+
+// sendFanOut - sends p to out following the fan out policy, returning false once ctx is done
+func (s *PrimeNumberSieve) sendFanOut(ctx context.Context, out chan<- int64, p int64) bool {
+	if s.fanOutPolicy == FanOutDrop {
+		select {
+		case <-ctx.Done():
+			return false
+		case out <- p:
+		default:
+		}
+		return true
+	}
+
+	select {
+	case <-ctx.Done():
+		return false
+	case out <- p:
+		return true
+	}
+}
@@ -0,0 +1,114 @@
+package sieve
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// sendAll - returns a closed-on-completion channel that receives each of values in order
+func sendAll(values ...int64) <-chan int64 {
+	ch := make(chan int64)
+	go func() {
+		defer close(ch)
+		for _, v := range values {
+			ch <- v
+		}
+	}()
+	return ch
+}
+
+// receiveAll - collects every value from ch until it is closed
+func receiveAll(ch <-chan int64) []int64 {
+	res := make([]int64, 0)
+	for v := range ch {
+		res = append(res, v)
+	}
+	return res
+}
+
+func TestMergePrimeStreams(t *testing.T) {
+	merged := MergePrimeStreams(
+		sendAll(2, 7, 17, 29),
+		sendAll(3, 5, 11, 13, 17),
+		sendAll(5, 19, 23, 31, 37),
+	)
+
+	assert.Equal(t, []int64{2, 3, 5, 7, 11, 13, 17, 19, 23, 29, 31, 37}, receiveAll(merged))
+}
+
+func TestMergePrimeStreamsEmpty(t *testing.T) {
+	assert.Equal(t, []int64{}, receiveAll(MergePrimeStreams()))
+	assert.Equal(t, []int64{2, 3}, receiveAll(MergePrimeStreams(sendAll(), sendAll(2, 3), sendAll())))
+}
+
+func TestMergePrimeStreamsShards(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+
+	merged := MergePrimeStreams(
+		sendAll(sieve.PrimesInRange(0, 5000)...),
+		sendAll(sieve.PrimesInRange(4000, 8000)...),
+		sendAll(sieve.PrimesInRange(6000, 10000)...),
+	)
+
+	assert.Equal(t, sieve.PrimesUpTo(10000), receiveAll(merged))
+}
+
+func TestPrimesFanOut(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	want := sieve.PrimesUpTo(200000)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	outs := sieve.PrimesFanOut(ctx, 2)
+	assert.Len(t, outs, 2)
+
+	// both consumers see the same primes in order, across several sieve windows
+	var wg sync.WaitGroup
+	got := make([][]int64, len(outs))
+	for i, out := range outs {
+		wg.Add(1)
+		go func(i int, out <-chan int64) {
+			defer wg.Done()
+			for len(got[i]) < len(want) {
+				got[i] = append(got[i], <-out)
+			}
+		}(i, out)
+	}
+	wg.Wait()
+	assert.Equal(t, want, got[0])
+	assert.Equal(t, want, got[1])
+
+	// and every channel is closed once the context is cancelled
+	cancel()
+	for _, out := range outs {
+		receiveAll(out)
+	}
+
+	assert.Empty(t, sieve.PrimesFanOut(context.Background(), 0))
+}
+
+func TestPrimesFanOutDropsForSlowConsumers(t *testing.T) {
+	sieve := NewPrimeNumberSieve(WithFanOut(10, FanOutDrop))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	outs := sieve.PrimesFanOut(ctx, 2)
+
+	// the fast consumer isn't held up by the slow one never reading, though it may see gaps of its own
+	got := make([]int64, 0)
+	for len(got) < 1000 {
+		got = append(got, <-outs[0])
+	}
+	for i, p := range got {
+		assert.True(t, sieve.IsPrime(p), "%d", p)
+		if i > 0 {
+			assert.Greater(t, p, got[i-1])
+		}
+	}
+	cancel()
+	receiveAll(outs[0])
+
+	// while the slow one only kept the primes that fit in its buffer
+	assert.Equal(t, sieve.PrimesUpTo(29), receiveAll(outs[1]))
+}
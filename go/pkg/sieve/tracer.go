@@ -0,0 +1,32 @@
+package sieve
+
+// Tracer - starts spans around sieve operations. It mirrors the shape of an OpenTelemetry tracer so one can be
+// adapted to it, without the package depending on OpenTelemetry itself
+type Tracer interface {
+	Start(name string) Span
+}
+
+// Span - a single traced operation, ended once the operation completes
+type Span interface {
+	SetAttribute(key string, value interface{})
+	End()
+}
+
+// Span attributes recorded by NthPrime
+const (
+	SpanAttrIndex      = "sieve.n"
+	SpanAttrUpperBound = "sieve.upper_bound"
+	SpanAttrAlgorithm  = "sieve.algorithm"
+	SpanAttrCached     = "sieve.cached"
+)
+
+// noopTracer - the default Tracer, which records nothing
+type noopTracer struct{}
+
+func (noopTracer) Start(string) Span { return noopSpan{} }
+
+// noopSpan - the span started by noopTracer
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, interface{}) {}
+func (noopSpan) End()                             {}
@@ -0,0 +1,63 @@
+package sieve
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTracer - records every span started on it
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []*fakeSpan
+}
+
+func (f *fakeTracer) Start(name string) Span {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	span := &fakeSpan{name: name, attributes: make(map[string]interface{})}
+	f.spans = append(f.spans, span)
+	return span
+}
+
+// fakeSpan - a span recorded by fakeTracer
+type fakeSpan struct {
+	name       string
+	attributes map[string]interface{}
+	ended      bool
+}
+
+func (f *fakeSpan) SetAttribute(key string, value interface{}) { f.attributes[key] = value }
+func (f *fakeSpan) End()                                       { f.ended = true }
+
+func TestWithTracer(t *testing.T) {
+	tracer := &fakeTracer{}
+	sieve := NewPrimeNumberSieve(WithTracer(tracer))
+
+	assert.Equal(t, int64(541), sieve.NthPrime(99))
+	assert.Equal(t, int64(71), sieve.NthPrime(19))
+
+	assert.Len(t, tracer.spans, 2)
+	span := tracer.spans[0]
+	assert.Equal(t, "sieve.NthPrime", span.name)
+	assert.True(t, span.ended)
+	assert.Equal(t, int64(99), span.attributes[SpanAttrIndex])
+	assert.GreaterOrEqual(t, span.attributes[SpanAttrUpperBound], int64(541))
+	assert.Equal(t, "segmented", span.attributes[SpanAttrAlgorithm])
+	assert.Equal(t, false, span.attributes[SpanAttrCached])
+
+	// the second query is answered from the first one's primes
+	assert.Equal(t, true, tracer.spans[1].attributes[SpanAttrCached])
+}
+
+func TestTracerNoopByDefault(t *testing.T) {
+	sieve := NewPrimeNumberSieve()
+	assert.Equal(t, noopTracer{}, sieve.tracer)
+	assert.Equal(t, int64(541), sieve.NthPrime(99))
+
+	// a nil tracer is also treated as no tracer
+	sieve = NewPrimeNumberSieve(WithTracer(nil))
+	assert.Equal(t, int64(541), sieve.NthPrime(99))
+}
@@ -0,0 +1,97 @@
+package sieve
+
+import (
+	"context"
+	"fmt"
+)
+
+// verifiedSieve - the segmented sieve with every result checked against the basic sieve of eratosthenes, see
+// WithDebugVerify. A disagreement panics, as it can only be a bug in the segmented sieve
+type verifiedSieve struct {
+	segmented *segmentedSieve
+}
+
+// sieve - implementation of the segmented sieve, verified
+func (v *verifiedSieve) sieve(n int64) []int64 {
+	res := v.segmented.sieve(n)
+	v.verify(n, res)
+	return res
+}
+
+// name - the segmented sieve's, verifying doesn't change which algorithm found the primes
+func (v *verifiedSieve) name() string {
+	return v.segmented.name()
+}
+
+// sieveContext - implementation of the contextSieve interface, verifying the primes unless the sieve was abandoned
+func (v *verifiedSieve) sieveContext(ctx context.Context, n int64) ([]int64, error) {
+	res, err := v.segmented.sieveContext(ctx, n)
+	if err == nil {
+		v.verify(n, res)
+	}
+	return res, err
+}
+
+// extendContext - implementation of the primeExtender interface, verifying every prime up to n, not just those added
+func (v *verifiedSieve) extendContext(ctx context.Context, primes []int64, bound, n int64) ([]int64, error) {
+	res, err := v.segmented.extendContext(ctx, primes, bound, n)
+	if err == nil {
+		v.verify(n, res)
+	}
+	return res, err
+}
+
+// verify - panics describing the first difference if primes aren't the primes up to n found by the basic sieve
+func (v *verifiedSieve) verify(n int64, primes []int64) {
+	want := (&basicSieveOfEratosthenes{}).sieve(n)
+	for i := 0; i < len(want) && i < len(primes); i++ {
+		if primes[i] != want[i] {
+			panic(fmt.Sprintf("sieve: segmented sieve up to %d found %d as prime %d, the basic sieve found %d", n, primes[i], i, want[i]))
+		}
+	}
+	if len(primes) != len(want) {
+		panic(fmt.Sprintf("sieve: segmented sieve found %d primes up to %d, the basic sieve found %d", len(primes), n, len(want)))
+	}
+}
+
+// verifyTable - checks decoded primes are exactly the primes up to bound: strictly increasing, each one prime by
+// Miller-Rabin, and none missing from the gaps between them. A count outside Chebyshev's bounds on pi(bound) is
+// rejected before testing anything, while a single missing prime is only found by testing every odd number in the gaps
+func verifyTable(primes []int64, bound int64) error {
+	if !withinChebyshevBounds(bound, int64(len(primes))) {
+		return fmt.Errorf("%w: %d primes up to %d is implausible", ErrCorruptTable, len(primes), bound)
+	}
+
+	prev := int64(1)
+	for i, p := range primes {
+		if i > 0 && p <= prev {
+			return fmt.Errorf("%w: %d at position %d does not follow %d", ErrCorruptTable, p, i, prev)
+		}
+		if !millerRabin(p) {
+			return fmt.Errorf("%w: %d at position %d is not prime", ErrCorruptTable, p, i)
+		}
+		if missing := firstPrimeIn(prev, p-1); missing != 0 {
+			return fmt.Errorf("%w: %d is missing before %d at position %d", ErrCorruptTable, missing, p, i)
+		}
+		prev = p
+	}
+	if missing := firstPrimeIn(prev, bound); missing != 0 {
+		return fmt.Errorf("%w: %d is missing after the last prime %d", ErrCorruptTable, missing, prev)
+	}
+	return nil
+}
+
+// firstPrimeIn - returns the smallest prime in (lo, hi], or 0 if there isn't one, testing 2 and then only the odd
+// numbers by Miller-Rabin
+func firstPrimeIn(lo, hi int64) int64 {
+	if lo < 2 && hi >= 2 {
+		return 2
+	}
+	// stepping past the largest int64 wraps negative, ending the loop
+	for n := lo + 1 + lo%2; n <= hi && n > 0; n += 2 {
+		if millerRabin(n) {
+			return n
+		}
+	}
+	return 0
+}
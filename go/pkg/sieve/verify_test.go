@@ -0,0 +1,119 @@
+package sieve
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithDebugVerify(t *testing.T) {
+	sieve := NewPrimeNumberSieve(WithDebugVerify(true))
+	assert.IsType(t, &verifiedSieve{}, sieve.newSieve())
+	assert.Equal(t, "segmented", sieve.newSieve().name())
+
+	// the segmented sieve agrees with the basic sieve across every way it's run, so nothing panics
+	assert.NotPanics(t, func() {
+		for _, n := range []int64{0, 1, 2, 99, 1000, 100000} {
+			assert.Equal(t, NewPrimeNumberSieve().NthPrime(n), sieve.NthPrime(n), "n=%d", n)
+		}
+		sieve.EnsurePrimesUpTo(3000000)
+		_, err := NewPrimeNumberSieve(WithDebugVerify(true), WithAdaptiveSegments(true)).NthPrimeCtx(context.Background(), 50000)
+		assert.NoError(t, err)
+	})
+
+	// other algorithms, and sieves without the option, aren't verified
+	assert.IsType(t, &basicSieveOfEratosthenes{}, NewPrimeNumberSieve(WithDebugVerify(true), WithAlgorithm(AlgorithmBasic)).newSieve())
+	assert.IsType(t, &segmentedSieve{}, NewPrimeNumberSieve().newSieve())
+}
+
+func TestDebugVerifyMismatch(t *testing.T) {
+	// a segment boundary bug dropping or misplacing a prime panics with the first difference
+	v := &verifiedSieve{segmented: &segmentedSieve{}}
+	primes := v.sieve(100)
+
+	wrong := append([]int64{}, primes...)
+	wrong[10] = 33
+	assert.PanicsWithValue(t, "sieve: segmented sieve up to 100 found 33 as prime 10, the basic sieve found 31", func() {
+		v.verify(100, wrong)
+	})
+	assert.PanicsWithValue(t, "sieve: segmented sieve found 24 primes up to 100, the basic sieve found 25", func() {
+		v.verify(100, primes[:24])
+	})
+	assert.NotPanics(t, func() { v.verify(100, primes) })
+}
+
+func TestLoadCorruptedTable(t *testing.T) {
+	saved := NewPrimeNumberSieve()
+	saved.PrimesUpTo(30)
+
+	var buf bytes.Buffer
+	assert.NoError(t, saved.SaveTo(&buf))
+	table := buf.Bytes()
+
+	// the header is the magic, version, bound (30) and count (10), each a single byte here. The gaps follow it,
+	// so changing the gap between 2 and 3 from 1 to 2 decodes the table as 2, 4, 6, 8, ...
+	corrupt := append([]byte{}, table...)
+	corrupt[8] = 2
+
+	sieve := NewPrimeNumberSieve(WithVerifyOnLoad(true))
+	err := sieve.LoadFrom(bytes.NewReader(corrupt))
+	assert.True(t, errors.Is(err, ErrCorruptTable))
+	assert.Contains(t, err.Error(), "4 at position 1 is not prime")
+	assert.Empty(t, sieve.cache.primes)
+
+	// a gap of 0 duplicates a prime
+	corrupt[8] = 0
+	err = sieve.LoadFrom(bytes.NewReader(corrupt))
+	assert.True(t, errors.Is(err, ErrCorruptTable))
+	assert.Contains(t, err.Error(), "does not follow")
+	assert.Empty(t, sieve.cache.primes)
+
+	// without verification the corrupted values are trusted
+	unverified := NewPrimeNumberSieve()
+	assert.NoError(t, unverified.LoadFrom(bytes.NewReader(corrupt)))
+	assert.Equal(t, int64(2), unverified.NthPrime(1))
+}
+
+func TestLoadTableMissingPrimes(t *testing.T) {
+	primes := (&segmentedSieve{}).sieve(100)
+
+	for _, c := range []struct {
+		name    string
+		primes  []int64
+		message string
+	}{
+		{"first two", primes[2:], "2 is missing before 5"},
+		{"one in the middle", append(append([]int64{}, primes[:10]...), primes[11:]...), "31 is missing before 37"},
+		{"the last", primes[:len(primes)-1], "97 is missing after the last prime 89"},
+		{"all of them", []int64{}, "0 primes up to 100 is implausible"},
+	} {
+		// a table that decodes cleanly, but drops primes below its bound
+		saved := NewPrimeNumberSieve()
+		saved.cache.primes, saved.cache.bound = c.primes, 100
+		var buf bytes.Buffer
+		assert.NoError(t, saved.SaveTo(&buf))
+
+		sieve := NewPrimeNumberSieve(WithVerifyOnLoad(true))
+		err := sieve.LoadFrom(bytes.NewReader(buf.Bytes()))
+		assert.True(t, errors.Is(err, ErrCorruptTable), c.name)
+		if assert.Error(t, err, c.name) {
+			assert.Contains(t, err.Error(), c.message, c.name)
+		}
+		assert.Empty(t, sieve.cache.primes, c.name)
+
+		// which unverified would have answered queries wrongly
+		unverified := NewPrimeNumberSieve()
+		assert.NoError(t, unverified.LoadFrom(bytes.NewReader(buf.Bytes())), c.name)
+		assert.NotEqual(t, int64(25), unverified.PrimePi(100), c.name)
+	}
+
+	// while every prime up to the bound passes
+	saved := NewPrimeNumberSieve()
+	saved.PrimesUpTo(100000)
+	var buf bytes.Buffer
+	assert.NoError(t, saved.SaveTo(&buf))
+	assert.NoError(t, NewPrimeNumberSieve(WithVerifyOnLoad(true)).LoadFrom(&buf))
+}
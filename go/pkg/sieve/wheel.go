@@ -0,0 +1,86 @@
+package sieve
+
+// wheelSieve - uses a sieve of Eratosthenes on a 2-3-5 wheel to return a list of primes from 2 - n.
+// Only the 8 of every 30 numbers coprime to 2, 3 and 5 can be prime past 5, so only they are tracked and marked,
+// skipping the other ~73% of positions up front and cutting the memory needed to a bit per wheel position
+type wheelSieve struct{}
+
+// name - implementation of the sieve interface
+func (w *wheelSieve) name() string {
+	return "wheel"
+}
+
+// wheelResidues - the residues mod 30 coprime to 30, the positions on the wheel that can be prime
+var wheelResidues = [8]int64{1, 7, 11, 13, 17, 19, 23, 29}
+
+// wheelSlots - the index of each residue mod 30 in wheelResidues, -1 for residues not on the wheel
+var wheelSlots = func() [30]int64 {
+	var slots [30]int64
+	for i := range slots {
+		slots[i] = -1
+	}
+	for i, r := range wheelResidues {
+		slots[r] = int64(i)
+	}
+	return slots
+}()
+
+// wheelValue - the number at position i of the wheel, 1, 7, 11, ..., 29, 31, 37, ...
+func wheelValue(i int64) int64 {
+	return i/8*30 + wheelResidues[i%8]
+}
+
+// wheelPosition - the position of v on the wheel, v must be coprime to 30
+func wheelPosition(v int64) int64 {
+	return v/30*8 + wheelSlots[v%30]
+}
+
+// sieve - implementation of the wheel sieve
+func (w *wheelSieve) sieve(n int64) []int64 {
+	// the primes dividing 30 are off the wheel, so they're added directly
+	res := make([]int64, 0)
+	for _, p := range []int64{2, 3, 5} {
+		if p <= n {
+			res = append(res, p)
+		}
+	}
+	if n < 7 {
+		return res
+	}
+
+	// count the positions up to n, every full turn has 8 followed by those residues of the last turn <= n
+	positions := n / 30 * 8
+	for _, r := range wheelResidues {
+		if r <= n%30 {
+			positions++
+		}
+	}
+	isComposite := newBitset(positions)
+
+	// position 0 is 1, which isn't prime, so sieving starts from 7. Like the basic sieve, marking for each prime p
+	// starts at p*p since any smaller multiple has a smaller factor. Only the multiples p*q with q also on the wheel
+	// are coprime to 30, so q steps along the wheel rather than through every number
+	for i := int64(1); ; i++ {
+		p := wheelValue(i)
+		if p > n/p {
+			break
+		}
+		if isComposite.get(i) {
+			continue
+		}
+		for j := i; ; j++ {
+			q := wheelValue(j)
+			if q > n/p {
+				break
+			}
+			isComposite.set(wheelPosition(p * q))
+		}
+	}
+
+	for i := int64(1); i < positions; i++ {
+		if !isComposite.get(i) {
+			res = append(res, wheelValue(i))
+		}
+	}
+	return res
+}
@@ -0,0 +1,74 @@
+package sieve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWheelSieveMatchesBasic(t *testing.T) {
+	for _, n := range []int64{-1, 0, 1, 2, 3, 4, 5, 6, 7, 29, 30, 31, 49, 541, 7919, 65537, 99991, 1000000} {
+		assert.Equal(t, (&basicSieveOfEratosthenes{}).sieve(n), (&wheelSieve{}).sieve(n), "n=%d", n)
+	}
+
+	// every bound through a few hundred turns of the wheel, so no residue is missed
+	for n := int64(0); n <= 6000; n++ {
+		if !assert.Equal(t, eratosthenes(n), (&wheelSieve{}).sieve(n), "n=%d", n) {
+			break
+		}
+	}
+}
+
+func TestWheelPositions(t *testing.T) {
+	for i := int64(0); i < 100; i++ {
+		v := wheelValue(i)
+		assert.Equal(t, int64(1), gcd(v, 30), "v=%d", v)
+		assert.Equal(t, i, wheelPosition(v), "v=%d", v)
+	}
+	assert.Equal(t, int64(-1), wheelSlots[15])
+}
+
+// eratosthenesMarks - how many times the basic sieve marks a composite up to n, once per multiple from p*p of each
+// prime up to sqrt(n)
+func eratosthenesMarks(n int64) int64 {
+	var marks int64
+	for _, p := range eratosthenes(isqrt(n)) {
+		marks += (n-p*p)/p + 1
+	}
+	return marks
+}
+
+// wheelMarks - how many times the wheel sieve marks a composite up to n, once per wheel position q from p to n/p of
+// each prime p from 7 up to sqrt(n)
+func wheelMarks(n int64) int64 {
+	var marks int64
+	for _, p := range eratosthenes(isqrt(n)) {
+		if p < 7 {
+			continue
+		}
+		for j := wheelPosition(p); wheelValue(j) <= n/p; j++ {
+			marks++
+		}
+	}
+	return marks
+}
+
+func BenchmarkWheelSieve(b *testing.B) {
+	n := int64(1000000)
+
+	b.Run("eratosthenes", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ReportMetric(float64(eratosthenesMarks(n)), "marks/op")
+		for i := 0; i < b.N; i++ {
+			eratosthenes(n)
+		}
+	})
+
+	b.Run("wheel", func(b *testing.B) {
+		b.ReportAllocs()
+		b.ReportMetric(float64(wheelMarks(n)), "marks/op")
+		for i := 0; i < b.N; i++ {
+			(&wheelSieve{}).sieve(n)
+		}
+	})
+}